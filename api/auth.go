@@ -20,3 +20,16 @@ func (authConfig *AuthConfig) Encode() string {
 	}
 	return base64.URLEncoding.EncodeToString(buf)
 }
+
+func DecodeAuthConfig(credentials string) (*AuthConfig, error) {
+	buf, err := base64.URLEncoding.DecodeString(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	authConfig := &AuthConfig{}
+	if err := json.Unmarshal(buf, authConfig); err != nil {
+		return nil, err
+	}
+	return authConfig, nil
+}
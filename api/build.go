@@ -13,6 +13,8 @@ package api
 import (
 	"archive/tar"
 	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -29,41 +31,29 @@ const (
 	DOCKERIGNORE = ".dockerignore"
 )
 
-func (client *DockerClient) BuildImage(path, tag string, quiet bool) (string, error) {
-	v := url.Values{}
-	v.Set("rm", "1")
-	if tag != "" {
-		v.Set("t", tag)
-	}
-	if quiet {
-		v.Set("q", "1")
-	}
-
-	uri := fmt.Sprintf("/v%s/build?%s", API_VERSION, v.Encode())
-
+func ResolveDockerfile(path string) (string, string, error) {
 	dockerfile := filepath.Clean(path)
 
 	fi, err := os.Lstat(dockerfile)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	fm := fi.Mode()
 	if fm.IsDir() {
 		dockerfile = filepath.Join(dockerfile, DOCKERFILE)
 		if _, err := os.Stat(dockerfile); os.IsNotExist(err) {
-			return "", fmt.Errorf("No Dockerfile found in %s", path)
+			return "", "", fmt.Errorf("No Dockerfile found in %s", path)
 		}
 	}
 
-	var (
-		root     = filepath.Dir(dockerfile)
-		filename = filepath.Base(dockerfile)
-	)
+	return filepath.Dir(dockerfile), filepath.Base(dockerfile), nil
+}
 
+func ReadDockerignore(root string) ([]string, error) {
 	ignore, err := ioutil.ReadFile(filepath.Join(root, DOCKERIGNORE))
 	if err != nil && !os.IsNotExist(err) {
-		return "", fmt.Errorf("Error reading .dockerignore: %s", err)
+		return nil, fmt.Errorf("Error reading .dockerignore: %s", err)
 	}
 
 	var excludes []string
@@ -76,150 +66,248 @@ func (client *DockerClient) BuildImage(path, tag string, quiet bool) (string, er
 		excludes = append(excludes, pattern)
 	}
 
-	fmt.Fprintf(client.out, "Sending build context to Docker daemon\n")
-	if !quiet && (log.GetLevel() < log.InfoLevel) {
-		fmt.Fprintf(client.out, "---> ")
+	return excludes, nil
+}
+
+func MatchExcludes(relFilePath string, excludes []string) (bool, error) {
+	for _, exclude := range excludes {
+		matched, err := filepath.Match(exclude, relFilePath)
+		if err != nil {
+			log.Errorf("Error matching: %s, pattern: %s", relFilePath, exclude)
+			return false, err
+		}
+		if matched {
+			if filepath.Clean(relFilePath) == "." {
+				log.Errorf("Can't exclude whole path, excluding pattern: %s", exclude)
+				continue
+			}
+			return true, nil
+		}
 	}
+	return false, nil
+}
 
-	pipeReader, pipeWriter := io.Pipe()
+type ContextFile struct {
+	Path  string
+	Name  string
+	Size  int64
+	IsDir bool
+}
 
-	go func() {
-		var (
-			files int64 = 0
-			total int64 = 0
-		)
+func ListBuildContext(root, filename string, excludes []string) ([]ContextFile, error) {
+	var files []ContextFile
+
+	seen := make(map[string]bool)
 
-		bufWriter := bufio.NewWriterSize(pipeWriter, 32*1024)
-		tarWriter := tar.NewWriter(bufWriter)
-		tmpWriter := bufio.NewWriterSize(nil, 32*1024)
-		defer tmpWriter.Reset(nil)
+	err := filepath.Walk(filepath.Join(root, "."), func(filePath string, f os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Can't stat file %s, error: %s", filePath, err)
+			return nil
+		}
+
+		relFilePath, err := filepath.Rel(root, filePath)
+		if err != nil || (relFilePath == "." && f.IsDir()) {
+			return nil
+		}
 
-		seen := make(map[string]bool)
+		skip := false
 
-		filepath.Walk(filepath.Join(root, "."), func(filePath string, f os.FileInfo, err error) error {
+		switch relFilePath {
+		default:
+			skip, err = MatchExcludes(relFilePath, excludes)
 			if err != nil {
-				log.Debugf("Can't stat file %s, error: %s", filePath, err)
-				return nil
+				log.Debugf("Error matching: %s, %s", relFilePath, err)
+				return err
+			}
+		case DOCKERFILE:
+			if filename != DOCKERFILE {
+				skip = true
 			}
+		case DOCKERIGNORE:
+		case filename:
+		}
 
-			relFilePath, err := filepath.Rel(root, filePath)
-			if err != nil || (relFilePath == "." && f.IsDir()) {
-				return nil
+		if skip {
+			log.WithField("", " Skipped").Debugf("---> %s", relFilePath)
+			if f.IsDir() {
+				return filepath.SkipDir
 			}
+			return nil
+		}
 
-			skip := false
-
-			switch relFilePath {
-			default:
-				skip, err = func() (bool, error) { // Excluding
-					for _, exclude := range excludes {
-						matched, err := filepath.Match(exclude, relFilePath)
-						if err != nil {
-							log.Errorf("Error matching: %s, pattern: %s", relFilePath, exclude)
-							return false, err
-						}
-						if matched {
-							if filepath.Clean(relFilePath) == "." {
-								log.Errorf("Can't exclude whole path, excluding pattern: %s", exclude)
-								continue
-							}
-							return true, nil
-						}
-					}
-					return false, nil
-				}()
-				if err != nil {
-					log.Debugf("Error matching: %s, %s", relFilePath, err)
+		if seen[relFilePath] {
+			return nil
+		}
+		seen[relFilePath] = true
+
+		files = append(files, ContextFile{
+			Path:  filePath,
+			Name:  relFilePath,
+			Size:  f.Size(),
+			IsDir: f.IsDir(),
+		})
+
+		return nil
+	})
+
+	return files, err
+}
+
+func writeBuildContext(out io.Writer, files []ContextFile, filename string, added func(string, int64)) error {
+	bufWriter := bufio.NewWriterSize(out, 32*1024)
+	tarWriter := tar.NewWriter(bufWriter)
+	tmpWriter := bufio.NewWriterSize(nil, 32*1024)
+	defer tmpWriter.Reset(nil)
+
+	for _, entry := range files {
+		var size int64
+
+		if err := func() error { // Adding a file to tar
+			fi, err := os.Lstat(entry.Path)
+			if err != nil {
+				log.Errorf("Can't get file info: %s, error: %s", entry.Path, err)
+				return err
+			}
+
+			size = fi.Size()
+
+			link := ""
+			if (fi.Mode() & os.ModeSymlink) != 0 {
+				if link, err = os.Readlink(entry.Path); err != nil {
+					log.Errorf("Can't read link to tar: %s, error: %s", entry.Path, err)
 					return err
 				}
-			case DOCKERFILE:
-				if filename != DOCKERFILE {
-					skip = true
-				}
-			case DOCKERIGNORE:
-			case filename:
 			}
 
-			if skip {
-				log.WithField("", " Skipped").Debugf("---> %s", relFilePath)
-				if f.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
+			hdr, err := tar.FileInfoHeader(fi, link)
+			if err != nil {
+				log.Errorf("Can't get file info header to tar: %s, error: %s", entry.Path, err)
+				return err
+			}
+
+			name := entry.Name
+			if fi.IsDir() && !strings.HasSuffix(name, "/") {
+				name = name + "/"
 			}
+			hdr.Name = name
 
-			if seen[relFilePath] {
-				return nil
+			if name == filename {
+				hdr.Name = DOCKERFILE
 			}
-			seen[relFilePath] = true
 
-			var size int64
+			if err := tarWriter.WriteHeader(hdr); err != nil {
+				log.Errorf("Can't write tar header, error: %s", err)
+				return err
+			}
 
-			if err := func() error { // Adding a file to tar
-				fi, err := os.Lstat(filePath)
+			if hdr.Typeflag == tar.TypeReg {
+				file, err := os.Open(entry.Path)
 				if err != nil {
-					log.Errorf("Can't get file info: %s, error: %s", filePath, err)
+					log.Errorf("Can't open file: %s, error: %s", entry.Path, err)
 					return err
 				}
 
-				size = fi.Size()
-
-				link := ""
-				if (fi.Mode() & os.ModeSymlink) != 0 {
-					if link, err = os.Readlink(filePath); err != nil {
-						log.Errorf("Can't read link to tar: %s, error: %s", filePath, err)
-						return err
-					}
+				tmpWriter.Reset(tarWriter)
+				defer tmpWriter.Reset(nil)
+				_, err = io.Copy(tmpWriter, file)
+				file.Close()
+				if err != nil {
+					log.Errorf("Can't write file to tar: %s, error: %s", entry.Path, err)
+					return err
 				}
-
-				hdr, err := tar.FileInfoHeader(fi, link)
+				err = tmpWriter.Flush()
 				if err != nil {
-					log.Errorf("Can't get file info header to tar: %s, error: %s", filePath, err)
+					log.Errorf("Can't flush file to tar, error: %s", err)
 					return err
 				}
+			}
 
-				name := relFilePath
-				if fi.IsDir() && !strings.HasSuffix(name, "/") {
-					name = name + "/"
-				}
-				hdr.Name = name
+			return nil
+		}(); err != nil {
+			log.Debugf("Can't add file %s to tar, error: %s", entry.Path, err)
+		}
 
-				if name == filename {
-					hdr.Name = DOCKERFILE
-				}
+		if added != nil {
+			added(entry.Name, size)
+		}
+	}
 
-				if err := tarWriter.WriteHeader(hdr); err != nil {
-					log.Errorf("Can't write tar header, error: %s", err)
-					return err
-				}
+	if err := tarWriter.Close(); err != nil {
+		log.Debugf("Can't close tar writer: %s", err)
+	}
 
-				if hdr.Typeflag == tar.TypeReg {
-					file, err := os.Open(filePath)
-					if err != nil {
-						log.Errorf("Can't open file: %s, error: %s", filePath, err)
-						return err
-					}
-
-					tmpWriter.Reset(tarWriter)
-					defer tmpWriter.Reset(nil)
-					_, err = io.Copy(tmpWriter, file)
-					file.Close()
-					if err != nil {
-						log.Errorf("Can't write file to tar: %s, error: %s", filePath, err)
-						return err
-					}
-					err = tmpWriter.Flush()
-					if err != nil {
-						log.Errorf("Can't flush file to tar, error: %s", err)
-						return err
-					}
-				}
+	return bufWriter.Flush()
+}
 
-				return nil
-			}(); err != nil {
-				log.Debugf("Can't add file %s to tar, error: %s", filePath, err)
-			}
+func BuildContextDigest(path string) (string, error) {
+	root, filename, err := ResolveDockerfile(path)
+	if err != nil {
+		return "", err
+	}
+
+	excludes, err := ReadDockerignore(root)
+	if err != nil {
+		return "", err
+	}
+
+	files, err := ListBuildContext(root, filename, excludes)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	if err := writeBuildContext(hash, files, filename, nil); err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (client *DockerClient) BuildImage(path, tag string, quiet bool) (string, error) {
+	v := url.Values{}
+	v.Set("rm", "1")
+	if tag != "" {
+		v.Set("t", tag)
+	}
+	if quiet {
+		v.Set("q", "1")
+	}
+
+	uri := fmt.Sprintf("/v%s/build?%s", client.apiVersion(), v.Encode())
+
+	root, filename, err := ResolveDockerfile(path)
+	if err != nil {
+		return "", err
+	}
+
+	excludes, err := ReadDockerignore(root)
+	if err != nil {
+		return "", err
+	}
+
+	contextFiles, err := ListBuildContext(root, filename, excludes)
+	if err != nil {
+		return "", err
+	}
+
+	if err := client.checkContext(contextFiles); err != nil {
+		return "", err
+	}
 
+	fmt.Fprintf(client.out, "Sending build context to Docker daemon\n")
+	if !quiet && (log.GetLevel() < log.InfoLevel) {
+		fmt.Fprintf(client.out, "---> ")
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		var (
+			files int64 = 0
+			total int64 = 0
+		)
+
+		if err := writeBuildContext(pipeWriter, contextFiles, filename, func(relFilePath string, size int64) {
 			files++
 			total += size
 
@@ -229,15 +317,10 @@ func (client *DockerClient) BuildImage(path, tag string, quiet bool) (string, er
 			log.WithFields(log.Fields{
 				"": fmt.Sprintf(" %7.2f KB", float64(size)/1000),
 			}).Infof("---> %s", relFilePath)
-
-			return nil
-		})
-
-		if err := tarWriter.Close(); err != nil {
-			log.Debugf("Can't close tar writer: %s", err)
+		}); err != nil {
+			log.Debugf("Can't write build context: %s", err)
 		}
 
-		bufWriter.Flush()
 		if err := pipeWriter.Close(); err != nil {
 			log.Debugf("Can't close pipe writer: %s", err)
 		}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildContextDigest(t *testing.T) {
+	root, err := ioutil.TempDir("", "talk2docker")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	writeFile := func(name, content string) {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	writeFile(DOCKERFILE, "FROM busybox:latest\nCOPY app.txt /\n")
+	writeFile(DOCKERIGNORE, "ignored.txt\n")
+	writeFile("app.txt", "version 1")
+	writeFile("ignored.txt", "version 1")
+
+	digest, err := BuildContextDigest(root)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	again, err := BuildContextDigest(root)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if again != digest {
+		t.Errorf("got %v\nwant %v", again, digest)
+	}
+
+	writeFile("ignored.txt", "version 2")
+	again, err = BuildContextDigest(root)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if again != digest {
+		t.Errorf("got %v\nwant %v", again, digest)
+	}
+
+	writeFile("app.txt", "version 2")
+	again, err = BuildContextDigest(root)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if again == digest {
+		t.Errorf("%v", "The digest should change along with the build context.")
+	}
+}
@@ -31,6 +31,9 @@ type DockerClient struct {
 	URL           *url.URL
 	HTTPClient    *http.Client
 	TLSConfig     *tls.Config
+	ContextRules  *ContextRules
+	Progress      ProgressRendererFactory
+	ApiVersion    string // API_VERSION if empty
 	monitorEvents int32
 	out           io.Writer
 }
@@ -85,7 +88,26 @@ func NewDockerClient(daemonUrl string, tlsConfig *tls.Config, timeout time.Durat
 
 	httpClient := newHTTPClient(u, tlsConfig, timeout)
 
-	return &DockerClient{u, httpClient, tlsConfig, 0, out}, nil
+	progress, err := GetProgressRendererFactory(DefaultProgressMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DockerClient{u, httpClient, tlsConfig, nil, progress, "", 0, out}, nil
+}
+
+// Returns a copy of the client which writes its output to out
+func (client *DockerClient) WithOutput(out io.Writer) *DockerClient {
+	copied := *client
+	copied.out = out
+	return &copied
+}
+
+func (client *DockerClient) apiVersion() string {
+	if client.ApiVersion == "" {
+		return API_VERSION
+	}
+	return client.ApiVersion
 }
 
 func (client *DockerClient) doRequest(method string, path string, body []byte, headers map[string]string) ([]byte, error) {
@@ -175,7 +197,7 @@ func (client *DockerClient) doStreamRequest(method string, path string, in io.Re
 			}
 		}
 
-		message, err := displayJSONMessagesStream(resp.Body, out)
+		message, err := displayJSONMessagesStream(resp.Body, client.Progress(out))
 		if quiet && (message != "") {
 			fmt.Fprintf(client.out, "%s", message)
 		}
@@ -33,7 +33,7 @@ func (client *DockerClient) ListContainers(all, size bool, limit int, since, bef
 		}
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/json?%s", API_VERSION, v.Encode())
+	uri := fmt.Sprintf("/v%s/containers/json?%s", client.apiVersion(), v.Encode())
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -60,7 +60,7 @@ func (client *DockerClient) CreateContainer(name string, config Config, hostConf
 		return "", err
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/create?%s", API_VERSION, v.Encode())
+	uri := fmt.Sprintf("/v%s/containers/create?%s", client.apiVersion(), v.Encode())
 	data, err := client.doRequest("POST", uri, buf, nil)
 	if err != nil {
 		return "", err
@@ -82,7 +82,7 @@ func (client *DockerClient) CreateContainer(name string, config Config, hostConf
 }
 
 func (client *DockerClient) InspectContainer(name string) (*ContainerInfo, error) {
-	uri := fmt.Sprintf("/v%s/containers/%s/json", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/containers/%s/json", client.apiVersion(), name)
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -96,7 +96,7 @@ func (client *DockerClient) InspectContainer(name string) (*ContainerInfo, error
 }
 
 func (client *DockerClient) StartContainer(name string) error {
-	uri := fmt.Sprintf("/v%s/containers/%s/start", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/containers/%s/start", client.apiVersion(), name)
 	if _, err := client.doRequest("POST", uri, nil, nil); err != nil {
 		return err
 	}
@@ -109,7 +109,7 @@ func (client *DockerClient) StopContainer(name string, timeToWait int) error {
 		v.Set("t", strconv.Itoa(timeToWait))
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/%s/stop?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/containers/%s/stop?%s", client.apiVersion(), name, v.Encode())
 	if _, err := client.doRequest("POST", uri, nil, nil); err != nil {
 		return err
 	}
@@ -123,7 +123,7 @@ func (client *DockerClient) RestartContainer(name string, timeToWait int) error
 		v.Set("t", strconv.Itoa(timeToWait))
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/%s/restart?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/containers/%s/restart?%s", client.apiVersion(), name, v.Encode())
 	if _, err := client.doRequest("POST", uri, nil, nil); err != nil {
 		return err
 	}
@@ -137,7 +137,7 @@ func (client *DockerClient) KillContainer(name, signal string) error {
 		v.Set("signal", signal)
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/%s/kill?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/containers/%s/kill?%s", client.apiVersion(), name, v.Encode())
 	if _, err := client.doRequest("POST", uri, nil, nil); err != nil {
 		return err
 	}
@@ -146,7 +146,7 @@ func (client *DockerClient) KillContainer(name, signal string) error {
 }
 
 func (client *DockerClient) PauseContainer(name string) error {
-	uri := fmt.Sprintf("/v%s/containers/%s/pause", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/containers/%s/pause", client.apiVersion(), name)
 	if _, err := client.doRequest("POST", uri, nil, nil); err != nil {
 		return err
 	}
@@ -154,7 +154,7 @@ func (client *DockerClient) PauseContainer(name string) error {
 }
 
 func (client *DockerClient) UnpauseContainer(name string) error {
-	uri := fmt.Sprintf("/v%s/containers/%s/unpause", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/containers/%s/unpause", client.apiVersion(), name)
 	if _, err := client.doRequest("POST", uri, nil, nil); err != nil {
 		return err
 	}
@@ -162,7 +162,7 @@ func (client *DockerClient) UnpauseContainer(name string) error {
 }
 
 func (client *DockerClient) WaitContainer(name string) (int, error) {
-	uri := fmt.Sprintf("/v%s/containers/%s/wait", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/containers/%s/wait", client.apiVersion(), name)
 	data, err := client.doRequest("POST", uri, nil, nil)
 	if err != nil {
 		return 0, err
@@ -184,7 +184,7 @@ func (client *DockerClient) RemoveContainer(name string, force bool) error {
 		v.Set("force", "1")
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/%s?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/containers/%s?%s", client.apiVersion(), name, v.Encode())
 	if _, err := client.doRequest("DELETE", uri, nil, nil); err != nil {
 		return err
 	}
@@ -212,7 +212,7 @@ func (client *DockerClient) GetContainerLogs(name string, follow, stdout, stderr
 		v.Set("tail", strconv.Itoa(tail))
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/%s/logs?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/containers/%s/logs?%s", client.apiVersion(), name, v.Encode())
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -271,7 +271,7 @@ const (
 )
 
 func (client *DockerClient) GetContainerChanges(name string) ([]Change, error) {
-	uri := fmt.Sprintf("/v%s/containers/%s/changes", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/containers/%s/changes", client.apiVersion(), name)
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -285,7 +285,7 @@ func (client *DockerClient) GetContainerChanges(name string) ([]Change, error) {
 }
 
 func (client *DockerClient) ExportContainer(name string) error {
-	uri := fmt.Sprintf("/v%s/containers/%s/export", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/containers/%s/export", client.apiVersion(), name)
 	if _, err := client.doStreamRequest("GET", uri, nil, nil, true); err != nil {
 		return err
 	}
@@ -304,7 +304,7 @@ func (client *DockerClient) CopyContainer(name, path string) error {
 		return err
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/%s/copy", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/containers/%s/copy", client.apiVersion(), name)
 	if _, err := client.doStreamRequest("POST", uri, bytes.NewReader(buf), nil, true); err != nil {
 		return err
 	}
@@ -317,7 +317,7 @@ func (client *DockerClient) GetContainerProcesses(name, ps_args string) (*Proces
 		v.Set("ps_args", ps_args)
 	}
 
-	uri := fmt.Sprintf("/v%s/containers/%s/top?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/containers/%s/top?%s", client.apiVersion(), name, v.Encode())
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -345,7 +345,7 @@ func (client *DockerClient) CommitContainer(name, repo, tag, comment, author str
 		v.Set("pause", "0")
 	}
 
-	uri := fmt.Sprintf("/v%s/commit?%s", API_VERSION, v.Encode())
+	uri := fmt.Sprintf("/v%s/commit?%s", client.apiVersion(), v.Encode())
 	data, err := client.doRequest("POST", uri, nil, nil)
 	if err != nil {
 		return "", err
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	dockerfileInstructionRegexp  = regexp.MustCompile("^([A-Za-z]+)(\\s+(.*))?$")
+	dockerfileContinuationRegexp = regexp.MustCompile("\\\\\\s*$")
+)
+
+var DockerfileInstructions = []string{
+	"FROM",
+	"MAINTAINER",
+	"RUN",
+	"CMD",
+	"LABEL",
+	"EXPOSE",
+	"ENV",
+	"ADD",
+	"COPY",
+	"ENTRYPOINT",
+	"VOLUME",
+	"USER",
+	"WORKDIR",
+	"ONBUILD",
+}
+
+type Instruction struct {
+	Line     int
+	Command  string
+	Value    string
+	Args     []string
+	JSONForm bool
+}
+
+func (instruction *Instruction) IsKnown() bool {
+	for _, command := range DockerfileInstructions {
+		if instruction.Command == command {
+			return true
+		}
+	}
+	return false
+}
+
+func (instruction *Instruction) String() string {
+	return instruction.Command + " " + instruction.Value
+}
+
+func ParseDockerfile(in io.Reader) ([]Instruction, error) {
+	var (
+		instructions []Instruction
+		scanner      = bufio.NewScanner(in)
+		lineno       = 0
+		start        = 0
+		buffer       = ""
+	)
+
+	flush := func() error {
+		line := strings.TrimSpace(buffer)
+		buffer = ""
+		if line == "" {
+			return nil
+		}
+
+		matches := dockerfileInstructionRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			return fmt.Errorf("Dockerfile line %d: Invalid instruction: %s", start, line)
+		}
+
+		instruction := Instruction{
+			Line:    start,
+			Command: strings.ToUpper(matches[1]),
+			Value:   strings.TrimSpace(matches[3]),
+		}
+		if instruction.Value == "" {
+			return fmt.Errorf("Dockerfile line %d: %s requires at least one argument", start, instruction.Command)
+		}
+
+		if strings.HasPrefix(instruction.Value, "[") {
+			var args []string
+			if err := json.Unmarshal([]byte(instruction.Value), &args); err == nil {
+				instruction.Args = args
+				instruction.JSONForm = true
+			}
+		}
+		if !instruction.JSONForm {
+			instruction.Args = strings.Fields(instruction.Value)
+		}
+
+		instructions = append(instructions, instruction)
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimLeft(scanner.Text(), " \t")
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if buffer == "" {
+			start = lineno
+		}
+
+		if dockerfileContinuationRegexp.MatchString(line) {
+			buffer += dockerfileContinuationRegexp.ReplaceAllLiteralString(line, "")
+			continue
+		}
+
+		buffer += line
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return instructions, nil
+}
+
+func ParseDockerfileFromPath(path string) ([]Instruction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseDockerfile(file)
+}
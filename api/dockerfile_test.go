@@ -0,0 +1,73 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	dockerfile := `# comment
+FROM busybox:latest
+
+run apt-get update && \
+    # comment inside a continuation
+    apt-get install -y curl
+CMD ["/bin/sh", "-c", "echo hello"]
+ENTRYPOINT [not json
+COPY a.txt b.txt /dst/
+`
+
+	instructions, err := ParseDockerfile(strings.NewReader(dockerfile))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expected := []Instruction{
+		{
+			Line:    2,
+			Command: "FROM",
+			Value:   "busybox:latest",
+			Args:    []string{"busybox:latest"},
+		},
+		{
+			Line:    4,
+			Command: "RUN",
+			Value:   "apt-get update && apt-get install -y curl",
+			Args:    []string{"apt-get", "update", "&&", "apt-get", "install", "-y", "curl"},
+		},
+		{
+			Line:     7,
+			Command:  "CMD",
+			Value:    `["/bin/sh", "-c", "echo hello"]`,
+			Args:     []string{"/bin/sh", "-c", "echo hello"},
+			JSONForm: true,
+		},
+		{
+			Line:    8,
+			Command: "ENTRYPOINT",
+			Value:   "[not json",
+			Args:    []string{"[not", "json"},
+		},
+		{
+			Line:    9,
+			Command: "COPY",
+			Value:   "a.txt b.txt /dst/",
+			Args:    []string{"a.txt", "b.txt", "/dst/"},
+		},
+	}
+
+	if !reflect.DeepEqual(instructions, expected) {
+		t.Errorf("got %v\nwant %v", instructions, expected)
+	}
+}
+
+func TestParseDockerfileErrors(t *testing.T) {
+	if _, err := ParseDockerfile(strings.NewReader("FROM\n")); err == nil {
+		t.Errorf("%v", "This should be an error.")
+	}
+
+	if _, err := ParseDockerfile(strings.NewReader("FROM busybox\n+++\n")); err == nil {
+		t.Errorf("%v", "This should be an error.")
+	}
+}
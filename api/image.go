@@ -18,7 +18,7 @@ func (client *DockerClient) ListImages(all bool, filters map[string][]string) ([
 		}
 	}
 
-	uri := fmt.Sprintf("/v%s/images/json?%s", API_VERSION, v.Encode())
+	uri := fmt.Sprintf("/v%s/images/json?%s", client.apiVersion(), v.Encode())
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -31,17 +31,22 @@ func (client *DockerClient) ListImages(all bool, filters map[string][]string) ([
 	return images, nil
 }
 
-func (client *DockerClient) PullImage(name string) (string, error) {
+func (client *DockerClient) PullImage(name, credentials string) (string, error) {
 	v := url.Values{}
 	v.Set("fromImage", name)
 
-	uri := fmt.Sprintf("/v%s/images/create?%s", API_VERSION, v.Encode())
+	uri := fmt.Sprintf("/v%s/images/create?%s", client.apiVersion(), v.Encode())
 
-	return client.doStreamRequest("POST", uri, nil, nil, false)
+	headers := map[string]string{}
+	if credentials != "" {
+		headers["X-Registry-Auth"] = credentials
+	}
+
+	return client.doStreamRequest("POST", uri, nil, headers, false)
 }
 
 func (client *DockerClient) GetImageHistory(name string) (ImageHistories, error) {
-	uri := fmt.Sprintf("/v%s/images/%s/history", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/images/%s/history", client.apiVersion(), name)
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -62,13 +67,13 @@ func (client *DockerClient) TagImage(name, repo, tag string, force bool) error {
 		v.Set("force", "1")
 	}
 
-	uri := fmt.Sprintf("/v%s/images/%s/tag?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/images/%s/tag?%s", client.apiVersion(), name, v.Encode())
 	_, err := client.doRequest("POST", uri, nil, nil)
 	return err
 }
 
 func (client *DockerClient) InspectImage(name string) (*ImageInfo, error) {
-	uri := fmt.Sprintf("/v%s/images/%s/json", API_VERSION, name)
+	uri := fmt.Sprintf("/v%s/images/%s/json", client.apiVersion(), name)
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -85,7 +90,7 @@ func (client *DockerClient) PushImage(name, tag, credentials string) (string, er
 	v := url.Values{}
 	v.Set("tag", tag)
 
-	uri := fmt.Sprintf("/v%s/images/%s/push?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/images/%s/push?%s", client.apiVersion(), name, v.Encode())
 
 	headers := map[string]string{}
 	headers["X-Registry-Auth"] = credentials
@@ -102,7 +107,7 @@ func (client *DockerClient) RemoveImage(name string, force, noprune bool) error
 		v.Set("noprune", "1")
 	}
 
-	uri := fmt.Sprintf("/v%s/images/%s?%s", API_VERSION, name, v.Encode())
+	uri := fmt.Sprintf("/v%s/images/%s?%s", client.apiVersion(), name, v.Encode())
 	data, err := client.doRequest("DELETE", uri, nil, nil)
 	if err != nil {
 		return err
@@ -129,7 +134,7 @@ func (client *DockerClient) SearchImages(term string) (ImageSearchResults, error
 	v := url.Values{}
 	v.Set("term", term)
 
-	uri := fmt.Sprintf("/v%s/images/search?%s", API_VERSION, v.Encode())
+	uri := fmt.Sprintf("/v%s/images/search?%s", client.apiVersion(), v.Encode())
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
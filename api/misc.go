@@ -1,8 +1,12 @@
 package api
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
 )
 
 func (client *DockerClient) Auth(auth *AuthConfig) (string, error) {
@@ -11,13 +15,13 @@ func (client *DockerClient) Auth(auth *AuthConfig) (string, error) {
 		return "", err
 	}
 
-	uri := fmt.Sprintf("/v%s/auth", API_VERSION)
+	uri := fmt.Sprintf("/v%s/auth", client.apiVersion())
 	_, err = client.doRequest("POST", uri, data, nil)
 	return auth.Encode(), err
 }
 
 func (client *DockerClient) Info() (*Info, error) {
-	uri := fmt.Sprintf("/v%s/info", API_VERSION)
+	uri := fmt.Sprintf("/v%s/info", client.apiVersion())
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -31,7 +35,7 @@ func (client *DockerClient) Info() (*Info, error) {
 }
 
 func (client *DockerClient) Version() (*Version, error) {
-	uri := fmt.Sprintf("/v%s/version", API_VERSION)
+	uri := fmt.Sprintf("/v%s/version", client.apiVersion())
 	data, err := client.doRequest("GET", uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -43,3 +47,44 @@ func (client *DockerClient) Version() (*Version, error) {
 	}
 	return version, nil
 }
+
+type PingResult struct {
+	Latency     time.Duration
+	CertExpiry  time.Time         // of the server certificate, zero without TLS
+	Certificate *x509.Certificate `json:"-" yaml:"-"` // the server certificate, nil without TLS
+}
+
+// Pings the daemon at /_ping, which doesn't require a version in the path
+func (client *DockerClient) Ping() (*PingResult, error) {
+	req, err := http.NewRequest("GET", client.URL.String()+"/_ping", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PingResult{
+		Latency: time.Since(start),
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, Error{StatusCode: resp.StatusCode, Status: resp.Status, msg: string(data)}
+	}
+
+	if (resp.TLS != nil) && (len(resp.TLS.PeerCertificates) > 0) {
+		result.Certificate = resp.TLS.PeerCertificates[0]
+		result.CertExpiry = result.Certificate.NotAfter
+	}
+
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_ping" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	docker, err := NewDockerClient(server.URL, &tls.Config{InsecureSkipVerify: true}, 0, os.Stdout)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	result, err := docker.Ping()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if result.Latency <= 0 {
+		t.Errorf("got %v\nwant %v", result.Latency, "a positive latency")
+	}
+	if (result.Certificate == nil) || !result.Certificate.Equal(server.Certificate()) {
+		t.Errorf("got %v\nwant %v", result.Certificate, "the server certificate")
+	}
+	if !result.CertExpiry.Equal(server.Certificate().NotAfter) {
+		t.Errorf("got %v\nwant %v", result.CertExpiry, server.Certificate().NotAfter)
+	}
+}
@@ -0,0 +1,304 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	PROGRESS_AUTO  = "auto"
+	PROGRESS_TTY   = "tty"
+	PROGRESS_PLAIN = "plain"
+	PROGRESS_JSON  = "json"
+
+	PLAIN_PROGRESS_STEP     = 10 // in percent
+	PLAIN_PROGRESS_INTERVAL = 5 * time.Second
+)
+
+var DefaultProgressMode = PROGRESS_AUTO
+
+type ProgressRenderer interface {
+	Render(jm *JSONMessage) (string, error)
+	Flush() error
+}
+
+type ProgressRendererFactory func(out io.Writer) ProgressRenderer
+
+func GetProgressRendererFactory(mode string) (ProgressRendererFactory, error) {
+	switch mode {
+	case PROGRESS_AUTO, "":
+		return func(out io.Writer) ProgressRenderer {
+			if IsTerminal(out) {
+				return NewTerminalRenderer(out)
+			}
+			return NewPlainRenderer(out)
+		}, nil
+	case PROGRESS_TTY:
+		return NewTerminalRenderer, nil
+	case PROGRESS_PLAIN:
+		return NewPlainRenderer, nil
+	case PROGRESS_JSON:
+		return NewJSONRenderer, nil
+	}
+	return nil, fmt.Errorf("Invalid progress mode: %s (auto, tty, plain or json)", mode)
+}
+
+// Redraws a line per layer in place with ANSI escape sequences
+type TerminalRenderer struct {
+	out io.Writer
+	ids map[string]int
+}
+
+func NewTerminalRenderer(out io.Writer) ProgressRenderer {
+	terminalWidth, terminalHeight = 0, 0
+	if fd, ok := getFd(out); ok {
+		terminalWidth, terminalHeight, _ = terminal.GetSize(fd)
+	}
+	return &TerminalRenderer{
+		out: out,
+		ids: map[string]int{},
+	}
+}
+
+func (renderer *TerminalRenderer) Render(jm *JSONMessage) (string, error) {
+	diff := 0
+	if jm.ID != "" && jm.IsProgress() {
+		line, ok := renderer.ids[jm.ID]
+		if !ok {
+			line = len(renderer.ids)
+			renderer.ids[jm.ID] = line
+			fmt.Fprintf(renderer.out, "\n")
+			diff = 0
+		} else {
+			diff = len(renderer.ids) - line
+		}
+		// <ESC>[{diff}A = move cursor up diff rows
+		fmt.Fprintf(renderer.out, "%c[%dA", 27, diff)
+	}
+	message, err := jm.Display(renderer.out, true)
+	if jm.ID != "" {
+		// <ESC>[{diff}B = move cursor down diff rows
+		fmt.Fprintf(renderer.out, "%c[%dB", 27, diff)
+	}
+	return message, err
+}
+
+func (renderer *TerminalRenderer) Flush() error {
+	return nil
+}
+
+// Prints a line per message, and progress in steps of percentage for logs
+type PlainRenderer struct {
+	out      io.Writer
+	percents map[string]int
+	times    map[string]time.Time
+}
+
+func NewPlainRenderer(out io.Writer) ProgressRenderer {
+	return &PlainRenderer{
+		out:      out,
+		percents: map[string]int{},
+		times:    map[string]time.Time{},
+	}
+}
+
+func (renderer *PlainRenderer) Render(jm *JSONMessage) (string, error) {
+	if !jm.IsProgress() {
+		return jm.Display(renderer.out, false)
+	}
+
+	if err := jm.Err(); err != nil {
+		return "", err
+	}
+
+	key := jm.ID + " " + jm.Status
+
+	percent := -1
+	if jm.Progress != nil {
+		percent = jm.Progress.Percentage()
+	}
+
+	if percent >= 0 {
+		step := (percent / PLAIN_PROGRESS_STEP) * PLAIN_PROGRESS_STEP
+		if last, ok := renderer.percents[key]; ok && step <= last {
+			return "", nil
+		}
+		renderer.percents[key] = step
+	} else {
+		if last, ok := renderer.times[key]; ok && time.Since(last) < PLAIN_PROGRESS_INTERVAL {
+			return "", nil
+		}
+		renderer.times[key] = time.Now()
+	}
+
+	if jm.ID != "" {
+		fmt.Fprintf(renderer.out, "%s: ", jm.ID)
+	}
+	if percent >= 0 {
+		fmt.Fprintf(renderer.out, "%s %d%% (%.3f MB/%.3f MB)\n", jm.Status, percent,
+			float64(jm.Progress.Current)/1000000, float64(jm.Progress.Total)/1000000)
+	} else if jm.Progress != nil {
+		fmt.Fprintf(renderer.out, "%s %.3f MB\n", jm.Status, float64(jm.Progress.Current)/1000000)
+	} else {
+		fmt.Fprintf(renderer.out, "%s %s\n", jm.Status, jm.ProgressMessage)
+	}
+
+	return "", nil
+}
+
+func (renderer *PlainRenderer) Flush() error {
+	return nil
+}
+
+// Passes each message through as a line of JSON
+type JSONRenderer struct {
+	out io.Writer
+}
+
+func NewJSONRenderer(out io.Writer) ProgressRenderer {
+	return &JSONRenderer{
+		out: out,
+	}
+}
+
+func (renderer *JSONRenderer) Render(jm *JSONMessage) (string, error) {
+	if _, err := fmt.Fprintf(renderer.out, "%s\n", jm.raw); err != nil {
+		return "", err
+	}
+
+	if err := jm.Err(); err != nil {
+		return "", err
+	}
+
+	switch {
+	case jm.IsProgress():
+		return "", nil
+	case jm.Stream != "":
+		return jm.Stream, nil
+	}
+	return jm.Status, nil
+}
+
+func (renderer *JSONRenderer) Flush() error {
+	return nil
+}
+
+func ResolveProgressMode(mode string, out io.Writer) string {
+	if (mode == PROGRESS_AUTO) || (mode == "") {
+		if IsTerminal(out) {
+			return PROGRESS_TTY
+		}
+		return PROGRESS_PLAIN
+	}
+	return mode
+}
+
+type PullProgress struct {
+	Status  string
+	Current int64
+	Total   int64
+	Layers  int
+	Done    int
+}
+
+func (p *PullProgress) Percentage() int {
+	if p.Total <= 0 {
+		return -1
+	}
+	return int(float64(p.Current) / float64(p.Total) * 100)
+}
+
+type layerProgress struct {
+	current int64
+	total   int64
+	done    bool
+}
+
+// Sums up the progress of all layers of an image, and reports it at every message
+type AggregateRenderer struct {
+	Digest string
+
+	layers map[string]*layerProgress
+	order  []string
+	status string
+	report func(progress PullProgress)
+}
+
+func NewAggregateRenderer(report func(progress PullProgress)) *AggregateRenderer {
+	return &AggregateRenderer{
+		layers: map[string]*layerProgress{},
+		report: report,
+	}
+}
+
+func (renderer *AggregateRenderer) Render(jm *JSONMessage) (string, error) {
+	if err := jm.Err(); err != nil {
+		return "", err
+	}
+
+	message := ""
+
+	switch {
+	case jm.ID == "":
+		if strings.HasPrefix(jm.Status, "Digest: ") {
+			renderer.Digest = strings.TrimPrefix(jm.Status, "Digest: ")
+		}
+		renderer.status = jm.Status
+		message = jm.Status
+	case jm.Status == "Pulling fs layer", jm.Status == "Waiting":
+		renderer.layer(jm.ID)
+	case jm.Status == "Downloading":
+		layer := renderer.layer(jm.ID)
+		if jm.Progress != nil {
+			layer.current = int64(jm.Progress.Current)
+			if jm.Progress.Total > 0 {
+				layer.total = int64(jm.Progress.Total)
+			}
+		}
+		renderer.status = jm.Status
+	case jm.Status == "Download complete", jm.Status == "Pull complete", jm.Status == "Already exists":
+		layer := renderer.layer(jm.ID)
+		layer.current = layer.total
+		layer.done = true
+	}
+
+	if renderer.report != nil {
+		renderer.report(renderer.Progress())
+	}
+
+	return message, nil
+}
+
+func (renderer *AggregateRenderer) layer(id string) *layerProgress {
+	layer, exists := renderer.layers[id]
+	if !exists {
+		layer = &layerProgress{}
+		renderer.layers[id] = layer
+		renderer.order = append(renderer.order, id)
+	}
+	return layer
+}
+
+func (renderer *AggregateRenderer) Progress() PullProgress {
+	progress := PullProgress{
+		Status: renderer.status,
+		Layers: len(renderer.order),
+	}
+	for _, id := range renderer.order {
+		layer := renderer.layers[id]
+		progress.Current += layer.current
+		progress.Total += layer.total
+		if layer.done {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+func (renderer *AggregateRenderer) Flush() error {
+	return nil
+}
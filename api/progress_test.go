@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const progressStream = `{"status":"Pulling fs layer","id":"511136ea3c5a"}
+{"status":"Downloading","progressDetail":{"current":100,"total":1000},"id":"511136ea3c5a"}
+{"status":"Downloading","progressDetail":{"current":150,"total":1000},"id":"511136ea3c5a"}
+{"status":"Downloading","progressDetail":{"current":1000,"total":1000},"id":"511136ea3c5a"}
+{"status":"Download complete","id":"511136ea3c5a"}
+{"status":"Status: Downloaded newer image for busybox:latest"}
+`
+
+func TestPlainRenderer(t *testing.T) {
+	var out bytes.Buffer
+
+	message, err := displayJSONMessagesStream(strings.NewReader(progressStream), NewPlainRenderer(&out))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if message != "Status: Downloaded newer image for busybox:latest" {
+		t.Errorf("got %q", message)
+	}
+
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Errorf("%v", "Plain output should not contain any escape sequences.")
+	}
+	if n := strings.Count(out.String(), "Downloading"); n != 2 {
+		t.Errorf("got %d progress lines\n%s", n, out.String())
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var out bytes.Buffer
+
+	_, err := displayJSONMessagesStream(strings.NewReader(progressStream), NewJSONRenderer(&out))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if out.String() != progressStream {
+		t.Errorf("got %v\nwant %v", out.String(), progressStream)
+	}
+
+	_, err = displayJSONMessagesStream(strings.NewReader(`{"errorDetail":{"code":401}}`), NewJSONRenderer(&out))
+	if err == nil {
+		t.Errorf("%v", "An error message should be returned as an error.")
+	}
+}
+
+func TestTerminalRendererWithoutFile(t *testing.T) {
+	var out bytes.Buffer
+
+	if _, err := displayJSONMessagesStream(strings.NewReader(progressStream), NewTerminalRenderer(&out)); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestAggregateRenderer(t *testing.T) {
+	stream := `{"status":"Pulling fs layer","id":"aaa"}
+{"status":"Pulling fs layer","id":"bbb"}
+{"status":"Downloading","progressDetail":{"current":100,"total":1000},"id":"aaa"}
+{"status":"Downloading","progressDetail":{"current":500,"total":3000},"id":"bbb"}
+{"status":"Download complete","id":"aaa"}
+{"status":"Digest: sha256:abcdef"}
+`
+
+	var last PullProgress
+	renderer := NewAggregateRenderer(func(progress PullProgress) {
+		last = progress
+	})
+
+	if _, err := displayJSONMessagesStream(strings.NewReader(stream), renderer); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	want := PullProgress{
+		Status:  "Digest: sha256:abcdef",
+		Current: 1500,
+		Total:   4000,
+		Layers:  2,
+		Done:    1,
+	}
+	if last != want {
+		t.Errorf("got %v\nwant %v", last, want)
+	}
+	if renderer.Digest != "sha256:abcdef" {
+		t.Errorf("got %v\nwant %v", renderer.Digest, "sha256:abcdef")
+	}
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	CONTEXT_ACTION_WARN = "warn"
+	CONTEXT_ACTION_FAIL = "fail"
+
+	CONTEXT_RULE_FILENAME = "filename"
+	CONTEXT_RULE_CONTENT  = "content"
+	CONTEXT_RULE_SIZE     = "size"
+
+	CONTEXT_SCAN_LIMIT = 1024 * 1024 // Only the head of each file is scanned for signatures
+)
+
+// Unset fields fall back to DefaultContextRules by WithDefaults
+type ContextRules struct {
+	Patterns   []string `yaml:"patterns,omitempty"`
+	Signatures []string `yaml:"signatures,omitempty"`
+	MaxSize    *int64   `yaml:"max-size,omitempty"` // in MB, 0 for unlimited
+	Action     string   `yaml:"action,omitempty"`   // warn or fail
+}
+
+var DefaultContextRules = ContextRules{
+	Patterns: []string{
+		"id_rsa",
+		"id_dsa",
+		"id_ecdsa",
+		"id_ed25519",
+		"*.pem",
+		"*.key",
+		"*.p12",
+		"*.pfx",
+		".env",
+		".netrc",
+		".aws/credentials",
+		".docker/config.json",
+		".dockercfg",
+	},
+	Signatures: []string{
+		"-----BEGIN ((RSA|DSA|EC|OPENSSH|ENCRYPTED|PGP) )?PRIVATE KEY( BLOCK)?-----",
+		"AKIA[0-9A-Z]{16}",
+		"aws_secret_access_key\\s*=",
+		"\"type\":\\s*\"service_account\"",
+	},
+	MaxSize: &defaultContextMaxSize,
+	Action:  CONTEXT_ACTION_WARN,
+}
+
+var defaultContextMaxSize int64 = 100
+
+// Returns the rules with DefaultContextRules for the unset fields, or an error for an invalid action
+func (rules *ContextRules) WithDefaults() (*ContextRules, error) {
+	merged := DefaultContextRules
+	if rules == nil {
+		return &merged, nil
+	}
+
+	if len(rules.Patterns) > 0 {
+		merged.Patterns = rules.Patterns
+	}
+	if len(rules.Signatures) > 0 {
+		merged.Signatures = rules.Signatures
+	}
+	if rules.MaxSize != nil {
+		merged.MaxSize = rules.MaxSize
+	}
+
+	switch rules.Action {
+	case "":
+	case CONTEXT_ACTION_WARN, CONTEXT_ACTION_FAIL:
+		merged.Action = rules.Action
+	default:
+		return nil, fmt.Errorf("Invalid action of context-rules: %s, it must be %s or %s", rules.Action, CONTEXT_ACTION_WARN, CONTEXT_ACTION_FAIL)
+	}
+
+	return &merged, nil
+}
+
+type ContextFinding struct {
+	Name    string
+	Rule    string
+	Message string
+}
+
+func ScanContext(files []ContextFile, rules *ContextRules) ([]ContextFinding, error) {
+	var (
+		findings   []ContextFinding
+		signatures []*regexp.Regexp
+	)
+
+	for _, signature := range rules.Signatures {
+		re, err := regexp.Compile(signature)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid signature: %s, %s", signature, err)
+		}
+		signatures = append(signatures, re)
+	}
+
+	for _, file := range files {
+		if file.IsDir {
+			continue
+		}
+
+		for _, pattern := range rules.Patterns {
+			matched, err := matchContextPattern(pattern, file.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid pattern: %s, %s", pattern, err)
+			}
+			if matched {
+				findings = append(findings, ContextFinding{
+					Name:    file.Name,
+					Rule:    CONTEXT_RULE_FILENAME,
+					Message: fmt.Sprintf("Matches the pattern %s", pattern),
+				})
+				break
+			}
+		}
+
+		if (rules.MaxSize != nil) && (*rules.MaxSize > 0) && (file.Size > *rules.MaxSize*1000000) {
+			findings = append(findings, ContextFinding{
+				Name:    file.Name,
+				Rule:    CONTEXT_RULE_SIZE,
+				Message: fmt.Sprintf("%.2f MB exceeds %d MB", float64(file.Size)/1000000, *rules.MaxSize),
+			})
+		}
+
+		if len(signatures) == 0 {
+			continue
+		}
+
+		head, err := readHead(file.Path, CONTEXT_SCAN_LIMIT)
+		if err != nil {
+			log.Debugf("Can't read file %s, error: %s", file.Path, err)
+			continue
+		}
+		for i, re := range signatures {
+			if re.Match(head) {
+				findings = append(findings, ContextFinding{
+					Name:    file.Name,
+					Rule:    CONTEXT_RULE_CONTENT,
+					Message: fmt.Sprintf("Contains the signature %s", rules.Signatures[i]),
+				})
+				break
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func matchContextPattern(pattern, name string) (bool, error) {
+	if !strings.Contains(pattern, "/") {
+		return filepath.Match(pattern, filepath.Base(name))
+	}
+
+	// Match against every trailing part of the path, e.g. a/.aws/credentials and .aws/credentials
+	parts := strings.Split(filepath.ToSlash(name), "/")
+	for i := range parts {
+		matched, err := filepath.Match(pattern, strings.Join(parts[i:], "/"))
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
+}
+
+func readHead(path string, limit int64) ([]byte, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, limit)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+func ListUploadContext(srcPath string) ([]ContextFile, error) {
+	var (
+		files   []ContextFile
+		rootDir = filepath.Dir(filepath.Clean(srcPath))
+	)
+
+	err := filepath.Walk(filepath.Clean(srcPath), func(filePath string, f os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("Can't stat file %s, error: %s", filePath, err)
+			return nil
+		}
+
+		relFilePath, err := filepath.Rel(rootDir, filePath)
+		if err != nil {
+			return nil
+		}
+
+		files = append(files, ContextFile{
+			Path:  filePath,
+			Name:  relFilePath,
+			Size:  f.Size(),
+			IsDir: f.IsDir(),
+		})
+
+		return nil
+	})
+
+	return files, err
+}
+
+func (client *DockerClient) checkContext(files []ContextFile) error {
+	rules, err := client.ContextRules.WithDefaults()
+	if err != nil {
+		return err
+	}
+
+	findings, err := ScanContext(files, rules)
+	if err != nil {
+		return err
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+
+	for _, finding := range findings {
+		if rules.Action == CONTEXT_ACTION_FAIL {
+			log.Errorf("%s: %s", finding.Name, finding.Message)
+		} else {
+			log.Warnf("%s: %s", finding.Name, finding.Message)
+		}
+	}
+
+	if rules.Action == CONTEXT_ACTION_FAIL {
+		return fmt.Errorf("Found %d suspicious file(s) in the context, aborted", len(findings))
+	}
+
+	return nil
+}
@@ -23,11 +23,16 @@ var (
 	terminalWidth, terminalHeight int
 )
 
-func getFd(out io.Writer) int {
+func getFd(out io.Writer) (int, bool) {
 	if file, ok := out.(*os.File); ok {
-		return int(file.Fd())
+		return int(file.Fd()), true
 	}
-	panic("unreachable")
+	return -1, false
+}
+
+func IsTerminal(out io.Writer) bool {
+	fd, ok := getFd(out)
+	return ok && terminal.IsTerminal(fd)
 }
 
 type JSONError struct {
@@ -89,6 +94,13 @@ func (p *JSONProgress) String() string {
 	return pbBox + numbersBox + timeLeftBox
 }
 
+func (p *JSONProgress) Percentage() int {
+	if p.Total <= 0 {
+		return -1
+	}
+	return int(float64(p.Current) / float64(p.Total) * 100)
+}
+
 type JSONMessage struct {
 	Stream          string        `json:"stream,omitempty"`
 	Status          string        `json:"status,omitempty"`
@@ -99,14 +111,27 @@ type JSONMessage struct {
 	Time            int64         `json:"time,omitempty"`
 	Error           *JSONError    `json:"errorDetail,omitempty"`
 	ErrorMessage    string        `json:"error,omitempty"` //deprecated
+
+	raw json.RawMessage
 }
 
-func (jm *JSONMessage) Display(out io.Writer, isTerminal bool) (string, error) {
+func (jm *JSONMessage) Err() error {
 	if jm.Error != nil {
 		if jm.Error.Code == 401 {
-			return "", fmt.Errorf("Authentication is required.")
+			return fmt.Errorf("Authentication is required.")
 		}
-		return "", jm.Error
+		return jm.Error
+	}
+	return nil
+}
+
+func (jm *JSONMessage) IsProgress() bool {
+	return jm.Progress != nil || jm.ProgressMessage != ""
+}
+
+func (jm *JSONMessage) Display(out io.Writer, isTerminal bool) (string, error) {
+	if err := jm.Err(); err != nil {
+		return "", err
 	}
 	var (
 		message = ""
@@ -144,54 +169,30 @@ func (jm *JSONMessage) Display(out io.Writer, isTerminal bool) (string, error) {
 	return message, nil
 }
 
-func displayJSONMessagesStream(in io.Reader, out io.Writer) (string, error) {
+func displayJSONMessagesStream(in io.Reader, renderer ProgressRenderer) (string, error) {
 	var (
-		dec        = json.NewDecoder(in)
-		ids        = map[string]int{}
-		diff       = 0
-		fd         = getFd(out)
-		isTerminal = terminal.IsTerminal(fd)
-		message    = ""
+		dec     = json.NewDecoder(in)
+		message = ""
 	)
 
-	//oldState, err := terminal.MakeRaw(fd)
-	//if err == nil {
-	//	defer terminal.Restore(fd, oldState)
-	//}
-	terminalWidth, terminalHeight, _ = terminal.GetSize(fd)
+	defer renderer.Flush()
 
 	for {
-		var jm JSONMessage
-		if err := dec.Decode(&jm); err != nil {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
 			if err == io.EOF {
 				break
 			}
 			return "", err
 		}
 
-		if jm.ID != "" && (jm.Progress != nil || jm.ProgressMessage != "") {
-			line, ok := ids[jm.ID]
-			if !ok {
-				line = len(ids)
-				ids[jm.ID] = line
-				if isTerminal {
-					fmt.Fprintf(out, "\n")
-				}
-				diff = 0
-			} else {
-				diff = len(ids) - line
-			}
-			if jm.ID != "" && isTerminal {
-				// <ESC>[{diff}A = move cursor up diff rows
-				fmt.Fprintf(out, "%c[%dA", 27, diff)
-			}
+		jm := JSONMessage{raw: raw}
+		if err := json.Unmarshal(raw, &jm); err != nil {
+			return "", err
 		}
+
 		var err error
-		message, err = jm.Display(out, isTerminal)
-		if jm.ID != "" && isTerminal {
-			// <ESC>[{diff}B = move cursor down diff rows
-			fmt.Fprintf(out, "%c[%dB", 27, diff)
-		}
+		message, err = renderer.Render(&jm)
 		if err != nil {
 			return "", err
 		}
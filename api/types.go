@@ -196,6 +196,7 @@ type Config struct {
 	NetworkDisabled bool
 	MacAddress      string
 	OnBuild         []string
+	Labels          map[string]string `json:",omitempty"`
 }
 
 // https://github.com/docker/docker/blob/master/daemon%2Finfo.go#L67
@@ -94,7 +94,7 @@ func (client *DockerClient) Upload(srcPath string, quiet bool) (string, error) {
 		v.Set("q", "1")
 	}
 
-	uri := fmt.Sprintf("/v%s/build?%s", API_VERSION, v.Encode())
+	uri := fmt.Sprintf("/v%s/build?%s", client.apiVersion(), v.Encode())
 
 	srcPath = filepath.Clean(srcPath)
 
@@ -112,6 +112,15 @@ func (client *DockerClient) Upload(srcPath string, quiet bool) (string, error) {
 		return "", err
 	}
 
+	files, err := ListUploadContext(srcPath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := client.checkContext(files); err != nil {
+		return "", err
+	}
+
 	pipeReader, pipeWriter := io.Pipe()
 
 	go func() {
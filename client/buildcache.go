@@ -0,0 +1,78 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+type BuildCache struct {
+	Builds []Build `yaml:"builds"`
+}
+
+type Build struct {
+	Dockerfile string `yaml:"dockerfile"`
+	Tag        string `yaml:"tag,omitempty"`
+	Digest     string `yaml:"digest"`
+	Image      string `yaml:"image"`
+}
+
+func GetBuildCachePath(configPath, hostName string) string {
+	return filepath.Join(filepath.Dir(os.ExpandEnv(configPath)), "build-cache", hostName)
+}
+
+func LoadBuildCache(path string) (*BuildCache, error) {
+	var cache BuildCache
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+
+	return &cache, nil
+}
+
+func (cache *BuildCache) GetBuild(dockerfile, tag string) *Build {
+	for _, build := range cache.Builds {
+		if (build.Dockerfile == dockerfile) && (build.Tag == tag) {
+			return &build
+		}
+	}
+	return nil
+}
+
+func (cache *BuildCache) SetBuild(newBuild *Build) {
+	for i, build := range cache.Builds {
+		if (build.Dockerfile == newBuild.Dockerfile) && (build.Tag == newBuild.Tag) {
+			cache.Builds[i] = *newBuild
+			return
+		}
+	}
+	cache.Builds = append(cache.Builds, *newBuild)
+}
+
+func (cache *BuildCache) SaveBuildCache(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path+".new", data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".new", path)
+}
@@ -1,12 +1,17 @@
 package client
 
 import (
+	"fmt"
 	"io"
 	"time"
 
 	"github.com/ailispaw/talk2docker/api"
 )
 
+const (
+	DEFAULT_TIMEOUT = 30 * time.Second
+)
+
 func NewDockerClient(configPath, hostName string, out io.Writer) (*api.DockerClient, error) {
 	config, err := LoadConfig(configPath)
 	if err != nil {
@@ -18,15 +23,49 @@ func NewDockerClient(configPath, hostName string, out io.Writer) (*api.DockerCli
 		return nil, err
 	}
 
+	docker, err := NewDockerClientForHost(host, out)
+	if err != nil {
+		return nil, err
+	}
+
+	docker.ContextRules = config.ContextRules
+
+	return docker, nil
+}
+
+// Creates a client for a host which may not be saved in the configuration file yet
+func NewDockerClientForHost(host *Host, out io.Writer) (*api.DockerClient, error) {
 	tlsConfig, err := host.getTLSConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	docker, err := api.NewDockerClient(host.URL, tlsConfig, 30*time.Second, out)
+	timeout, err := host.GetTimeout()
 	if err != nil {
 		return nil, err
 	}
 
+	docker, err := api.NewDockerClient(host.URL, tlsConfig, timeout, out)
+	if err != nil {
+		return nil, err
+	}
+
+	docker.ApiVersion = host.ApiVersion
+
 	return docker, nil
 }
+
+// Returns the timeout of the host, or DEFAULT_TIMEOUT if not specified
+func (host *Host) GetTimeout() (time.Duration, error) {
+	if host.Timeout == "" {
+		return DEFAULT_TIMEOUT, nil
+	}
+	timeout, err := time.ParseDuration(host.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid duration %s, must be positive", host.Timeout)
+	}
+	return timeout, nil
+}
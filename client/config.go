@@ -7,12 +7,18 @@ import (
 	"path/filepath"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/ailispaw/talk2docker/api"
 )
 
 type Config struct {
-	Default    string     `yaml:"default"`
-	Hosts      []Host     `yaml:"hosts"`
-	Registries []Registry `yaml:"registries,omitempty"`
+	Version      int               `yaml:"version"`
+	Default      string            `yaml:"default"`
+	Hosts        []Host            `yaml:"hosts"`
+	Registries   []Registry        `yaml:"registries,omitempty"`
+	ContextRules *api.ContextRules `yaml:"context-rules,omitempty"`
+	GC           *GCRules          `yaml:"gc,omitempty"`
+	Display      *Display          `yaml:"display,omitempty"`
 }
 
 type Host struct {
@@ -24,6 +30,23 @@ type Host struct {
 	TLSCert     string `yaml:"tls-cert,omitempty"`
 	TLSKey      string `yaml:"tls-key,omitempty"`
 	TLSVerify   bool   `yaml:"tls-verify,omitempty"`
+
+	TLSServerName   string `yaml:"tls-server-name,omitempty"`  // the server name to verify instead of the host in the URL
+	TLSMinVersion   string `yaml:"tls-min-version,omitempty"`  // 1.0, 1.1, 1.2 or 1.3
+	Timeout         string `yaml:"timeout,omitempty"`          // duration to connect, e.g. 10s
+	ApiVersion      string `yaml:"api-version,omitempty"`      // Docker Remote API version to use, e.g. 1.16
+	DefaultRegistry string `yaml:"default-registry,omitempty"` // registry for image names without one
+}
+
+type GCRules struct {
+	Keep      int      `yaml:"keep,omitempty"`       // the number of the newest tags to keep per repository
+	KeepTags  []string `yaml:"keep-tags,omitempty"`  // patterns of tags to keep
+	OlderThan string   `yaml:"older-than,omitempty"` // duration, e.g. 720h
+}
+
+type Display struct {
+	HumanUnits   bool `yaml:"human-units,omitempty"`   // sizes in KB, MB, GB instead of MB
+	RelativeTime bool `yaml:"relative-time,omitempty"` // times like "3 hours ago"
 }
 
 type Registry struct {
@@ -41,27 +64,19 @@ const (
 func getDefaultConfig() *Config {
 	var config Config
 
+	config.Version = CONFIG_VERSION
 	config.Default = "default"
 	config.Hosts = []Host{}
 
-	var host Host
-	host.Name = config.Default
-	url := os.Getenv("DOCKER_HOST")
-	if url == "" {
-		url = DOCKER_SOCKET
-	}
-	host.URL = url
-
-	certPath := os.Getenv("DOCKER_CERT_PATH")
-	if certPath != "" {
-		host.TLS = true
-		host.TLSCaCert = filepath.Join(certPath, "ca.pem")
-		host.TLSCert = filepath.Join(certPath, "cert.pem")
-		host.TLSKey = filepath.Join(certPath, "key.pem")
-		host.TLSVerify = true
+	host := getEnvHost(config.Default)
+	if host == nil {
+		host = &Host{
+			Name: config.Default,
+			URL:  DOCKER_SOCKET,
+		}
 	}
 
-	config.Hosts = append(config.Hosts, host)
+	config.Hosts = append(config.Hosts, *host)
 
 	return &config
 }
@@ -69,6 +84,34 @@ func getDefaultConfig() *Config {
 func LoadConfig(path string) (*Config, error) {
 	path = os.ExpandEnv(path)
 
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		config, version, err := MigrateConfig(data)
+		if (err != nil) || (version == CONFIG_VERSION) {
+			return config, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Creates or migrates it exclusively, unless this process has locked it already
+	if !isLocked(path) {
+		lock, err := LockConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Unlock()
+	}
+
+	return UpgradeConfig(path)
+}
+
+// Creates the default configuration file, or migrates it to CONFIG_VERSION with a backup,
+// where the caller must lock it
+func UpgradeConfig(path string) (*Config, error) {
+	path = os.ExpandEnv(path)
+
+	// Re-read it, which another process may have upgraded already
 	data, err := ioutil.ReadFile(path)
 	if os.IsNotExist(err) {
 		config := getDefaultConfig()
@@ -78,6 +121,24 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	config, version, err := MigrateConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if version < CONFIG_VERSION {
+		if err := backupConfig(path, data, version); err != nil {
+			return nil, err
+		}
+		if err := config.SaveConfig(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+func ParseConfig(data []byte) (*Config, error) {
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, err
@@ -103,6 +164,16 @@ func (config *Config) GetHost(name string) (*Host, error) {
 }
 
 func (config *Config) SaveConfig(path string) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	return WriteConfig(path, data)
+}
+
+// Replaces the configuration file with data, keeping the previous one as .bak
+func WriteConfig(path string, data []byte) error {
 	path = os.ExpandEnv(path)
 
 	os.Remove(path + ".new")
@@ -115,10 +186,6 @@ func (config *Config) SaveConfig(path string) error {
 	defer file.Close()
 	defer os.Remove(path + ".new")
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return err
-	}
 	if _, err := file.Write(data); err != nil {
 		return err
 	}
@@ -129,6 +196,11 @@ func (config *Config) SaveConfig(path string) error {
 	}
 
 	file.Close()
-	os.Remove(path)
-	return os.Rename(path+".new", path)
+
+	// Rename over the old one so that readers never miss the file
+	if err := os.Rename(path+".new", path); err != nil {
+		os.Remove(path)
+		return os.Rename(path+".new", path)
+	}
+	return nil
 }
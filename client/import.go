@@ -0,0 +1,206 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	IMPORT_MACHINE     = "machine"
+	IMPORT_BOOT2DOCKER = "boot2docker"
+	IMPORT_ENV         = "env"
+
+	BOOT2DOCKER_URL = "tcp://192.168.59.103:2376"
+
+	HOST_ADDED     = "Add"
+	HOST_UPDATED   = "Update"
+	HOST_UNCHANGED = "Unchanged"
+)
+
+// A host found by DiscoverHosts with where it comes from
+type ImportedHost struct {
+	Host   Host
+	Source string
+}
+
+// Finds hosts from docker-machine, boot2docker and the environment variables in home
+func DiscoverHosts(home string, sources []string) ([]ImportedHost, error) {
+	var hosts []ImportedHost
+
+	for _, source := range sources {
+		switch source {
+		case IMPORT_MACHINE:
+			found, err := discoverMachineHosts(filepath.Join(home, ".docker", "machine", "machines"))
+			if err != nil {
+				return nil, err
+			}
+			hosts = append(hosts, found...)
+		case IMPORT_BOOT2DOCKER:
+			found, err := discoverBoot2DockerHosts(filepath.Join(home, ".boot2docker", "certs"))
+			if err != nil {
+				return nil, err
+			}
+			hosts = append(hosts, found...)
+		case IMPORT_ENV:
+			if host := getEnvHost(IMPORT_ENV); host != nil {
+				hosts = append(hosts, ImportedHost{*host, IMPORT_ENV})
+			}
+		default:
+			return nil, fmt.Errorf("Invalid source: %s (%s, %s or %s)", source, IMPORT_MACHINE, IMPORT_BOOT2DOCKER, IMPORT_ENV)
+		}
+	}
+
+	return hosts, nil
+}
+
+// Reads */config.json of docker-machine
+func discoverMachineHosts(dir string) ([]ImportedHost, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "config.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var hosts []ImportedHost
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var machine struct {
+			Name   string
+			Driver struct {
+				IPAddress string
+			}
+			HostOptions struct {
+				AuthOptions struct {
+					CaCertPath     string
+					ClientCertPath string
+					ClientKeyPath  string
+				}
+			}
+		}
+		if err := json.Unmarshal(data, &machine); err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		machineDir := filepath.Dir(path)
+		if machine.Name == "" {
+			machine.Name = filepath.Base(machineDir)
+		}
+		if machine.Driver.IPAddress == "" {
+			continue
+		}
+
+		auth := machine.HostOptions.AuthOptions
+		host := Host{
+			Name:        machine.Name,
+			URL:         fmt.Sprintf("tcp://%s:2376", machine.Driver.IPAddress),
+			Description: "docker-machine",
+			TLS:         true,
+			TLSCaCert:   firstExisting(auth.CaCertPath, filepath.Join(machineDir, "ca.pem")),
+			TLSCert:     firstExisting(auth.ClientCertPath, filepath.Join(machineDir, "cert.pem")),
+			TLSKey:      firstExisting(auth.ClientKeyPath, filepath.Join(machineDir, "key.pem")),
+			TLSVerify:   true,
+		}
+		hosts = append(hosts, ImportedHost{host, IMPORT_MACHINE})
+	}
+
+	return hosts, nil
+}
+
+// Finds cert directories of boot2docker VMs, which listen on the default address
+func discoverBoot2DockerHosts(dir string) ([]ImportedHost, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "ca.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var hosts []ImportedHost
+	for _, path := range paths {
+		certDir := filepath.Dir(path)
+		host := Host{
+			Name:        filepath.Base(certDir),
+			URL:         BOOT2DOCKER_URL,
+			Description: "boot2docker",
+			TLS:         true,
+			TLSCaCert:   path,
+			TLSCert:     filepath.Join(certDir, "cert.pem"),
+			TLSKey:      filepath.Join(certDir, "key.pem"),
+			TLSVerify:   true,
+		}
+		hosts = append(hosts, ImportedHost{host, IMPORT_BOOT2DOCKER})
+	}
+
+	return hosts, nil
+}
+
+// Returns a host from DOCKER_HOST, DOCKER_CERT_PATH and DOCKER_TLS_VERIFY, or nil without DOCKER_HOST
+func getEnvHost(name string) *Host {
+	url := os.Getenv("DOCKER_HOST")
+	if url == "" {
+		return nil
+	}
+
+	host := &Host{
+		Name: name,
+		URL:  url,
+	}
+
+	certPath := os.Getenv("DOCKER_CERT_PATH")
+	if certPath != "" {
+		host.TLS = true
+		host.TLSCaCert = filepath.Join(certPath, "ca.pem")
+		host.TLSCert = filepath.Join(certPath, "cert.pem")
+		host.TLSKey = filepath.Join(certPath, "key.pem")
+		host.TLSVerify = true
+	}
+	if os.Getenv("DOCKER_TLS_VERIFY") != "" {
+		host.TLS = true
+		host.TLSVerify = true
+	}
+
+	return host
+}
+
+func firstExisting(paths ...string) string {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return paths[len(paths)-1]
+}
+
+// Adds the host, or updates the host with the same name keeping its description
+// and per-host settings, and returns what it did
+func (config *Config) ImportHost(host Host) string {
+	for i, h := range config.Hosts {
+		if h.Name == host.Name {
+			if h.Description != "" {
+				host.Description = h.Description
+			}
+			host.TLSServerName = h.TLSServerName
+			host.TLSMinVersion = h.TLSMinVersion
+			host.Timeout = h.Timeout
+			host.ApiVersion = h.ApiVersion
+			host.DefaultRegistry = h.DefaultRegistry
+			if h == host {
+				return HOST_UNCHANGED
+			}
+			config.Hosts[i] = host
+			return HOST_UPDATED
+		}
+	}
+	config.Hosts = append(config.Hosts, host)
+	return HOST_ADDED
+}
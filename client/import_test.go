@@ -0,0 +1,91 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverHosts(t *testing.T) {
+	home, err := ioutil.TempDir("", "talk2docker")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(home)
+
+	machineDir := filepath.Join(home, ".docker", "machine", "machines", "dev")
+	os.MkdirAll(machineDir, 0700)
+	ioutil.WriteFile(filepath.Join(machineDir, "config.json"), []byte(`{
+		"Name": "dev",
+		"Driver": {"IPAddress": "192.168.99.100"},
+		"HostOptions": {"AuthOptions": {"CaCertPath": "/nonexistent/ca.pem"}}
+	}`), 0600)
+	ioutil.WriteFile(filepath.Join(machineDir, "ca.pem"), []byte{}, 0600)
+
+	certDir := filepath.Join(home, ".boot2docker", "certs", "boot2docker-vm")
+	os.MkdirAll(certDir, 0700)
+	ioutil.WriteFile(filepath.Join(certDir, "ca.pem"), []byte{}, 0600)
+
+	os.Setenv("DOCKER_HOST", "tcp://10.0.0.1:2375")
+	os.Unsetenv("DOCKER_CERT_PATH")
+	os.Unsetenv("DOCKER_TLS_VERIFY")
+	defer os.Unsetenv("DOCKER_HOST")
+
+	hosts, err := DiscoverHosts(home, []string{IMPORT_MACHINE, IMPORT_BOOT2DOCKER, IMPORT_ENV})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(hosts) != 3 {
+		t.Fatalf("got %v\nwant %v", hosts, "3 hosts")
+	}
+
+	expected := Host{
+		Name:        "dev",
+		URL:         "tcp://192.168.99.100:2376",
+		Description: "docker-machine",
+		TLS:         true,
+		TLSCaCert:   filepath.Join(machineDir, "ca.pem"),
+		TLSCert:     filepath.Join(machineDir, "cert.pem"),
+		TLSKey:      filepath.Join(machineDir, "key.pem"),
+		TLSVerify:   true,
+	}
+	if hosts[0].Host != expected {
+		t.Errorf("got %v\nwant %v", hosts[0].Host, expected)
+	}
+	if (hosts[1].Host.Name != "boot2docker-vm") || (hosts[1].Host.URL != BOOT2DOCKER_URL) {
+		t.Errorf("got %v\nwant %v", hosts[1].Host, "boot2docker-vm")
+	}
+	if (hosts[2].Host.URL != "tcp://10.0.0.1:2375") || hosts[2].Host.TLS {
+		t.Errorf("got %v\nwant %v", hosts[2].Host, "tcp://10.0.0.1:2375 without TLS")
+	}
+
+	if _, err := DiscoverHosts(home, []string{"vagrant"}); err == nil {
+		t.Errorf("%v", "An unknown source should be an error.")
+	}
+}
+
+func TestImportHost(t *testing.T) {
+	config := &Config{
+		Hosts: []Host{{Name: "dev", URL: "tcp://192.168.99.100:2376", Description: "My VM", Timeout: "60s", ApiVersion: "1.15"}},
+	}
+
+	if action := config.ImportHost(Host{Name: "dev", URL: "tcp://192.168.99.100:2376"}); action != HOST_UNCHANGED {
+		t.Errorf("got %v\nwant %v", action, HOST_UNCHANGED)
+	}
+	if action := config.ImportHost(Host{Name: "dev", URL: "tcp://192.168.99.101:2376"}); action != HOST_UPDATED {
+		t.Errorf("got %v\nwant %v", action, HOST_UPDATED)
+	}
+	if config.Hosts[0].Description != "My VM" {
+		t.Errorf("got %v\nwant %v", config.Hosts[0].Description, "My VM")
+	}
+	if (config.Hosts[0].Timeout != "60s") || (config.Hosts[0].ApiVersion != "1.15") {
+		t.Errorf("got %v\nwant %v", config.Hosts[0], "the per-host settings kept")
+	}
+	if action := config.ImportHost(Host{Name: "env", URL: "tcp://10.0.0.1:2375"}); action != HOST_ADDED {
+		t.Errorf("got %v\nwant %v", action, HOST_ADDED)
+	}
+	if len(config.Hosts) != 2 {
+		t.Errorf("got %v\nwant %v", len(config.Hosts), 2)
+	}
+}
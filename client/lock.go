@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	LOCK_TIMEOUT  = 10 * time.Second
+	LOCK_INTERVAL = 100 * time.Millisecond
+)
+
+// An advisory lock of the configuration file to load, modify and save it exclusively
+type ConfigLock struct {
+	path string
+	file *os.File
+}
+
+// Paths locked by this process, where LoadConfig must not lock again
+var (
+	lockedPaths      = map[string]bool{}
+	lockedPathsMutex sync.Mutex
+)
+
+func isLocked(path string) bool {
+	lockedPathsMutex.Lock()
+	defer lockedPathsMutex.Unlock()
+	return lockedPaths[path]
+}
+
+func LockConfig(path string) (*ConfigLock, error) {
+	path = os.ExpandEnv(path)
+
+	os.Mkdir(filepath.Dir(path), 0700)
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(LOCK_TIMEOUT)
+	for {
+		err := tryLockFile(file)
+		if err == nil {
+			lockedPathsMutex.Lock()
+			lockedPaths[path] = true
+			lockedPathsMutex.Unlock()
+			return &ConfigLock{path, file}, nil
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("%s is locked by another process: %s", path, err)
+		}
+		time.Sleep(LOCK_INTERVAL)
+	}
+}
+
+func (lock *ConfigLock) Unlock() error {
+	lockedPathsMutex.Lock()
+	delete(lockedPaths, lock.path)
+	lockedPathsMutex.Unlock()
+
+	unlockFile(lock.file)
+	return lock.file.Close()
+}
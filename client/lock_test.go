@@ -0,0 +1,49 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talk2docker")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+
+	lock, err := LockConfig(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// Another open of the lock file, as another process does
+	file, err := os.OpenFile(path+".lock", os.O_RDWR, 0600)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer file.Close()
+
+	if err := tryLockFile(file); err == nil {
+		t.Errorf("got %v\nwant %v", err, "an error while locked")
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Errorf("got %v\nwant %v", err, nil)
+	}
+
+	if err := tryLockFile(file); err != nil {
+		t.Errorf("got %v\nwant %v", err, nil)
+	}
+	unlockFile(file)
+
+	lock, err = LockConfig(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	lock.Unlock()
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package client
+
+import (
+	"os"
+	"syscall"
+)
+
+func tryLockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The version of the configuration file format that this talk2docker writes
+const CONFIG_VERSION = 1
+
+// Upgrades a raw configuration from a version to the next one
+type migration func(config map[interface{}]interface{}) error
+
+// migrations[i] upgrades version i to i+1
+var migrations = []migration{
+	migrateToV1,
+}
+
+// Version 1 only adds the version key to the configuration without it
+func migrateToV1(config map[interface{}]interface{}) error {
+	return nil
+}
+
+// Upgrades the configuration in data to CONFIG_VERSION,
+// and returns it with the version of data
+func MigrateConfig(data []byte) (*Config, int, error) {
+	raw := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+
+	version := 0
+	if v, ok := raw["version"]; ok {
+		if version, ok = v.(int); !ok {
+			return nil, 0, fmt.Errorf("Invalid version: %v", v)
+		}
+	}
+	if version > CONFIG_VERSION {
+		return nil, version, fmt.Errorf("The configuration version %d is newer than %d supported, upgrade talk2docker", version, CONFIG_VERSION)
+	}
+	if version == CONFIG_VERSION {
+		config, err := ParseConfig(data)
+		return config, version, err
+	}
+
+	for i := version; i < CONFIG_VERSION; i++ {
+		if err := migrations[i](raw); err != nil {
+			return nil, version, fmt.Errorf("Failed to migrate the configuration to version %d: %s", i+1, err)
+		}
+	}
+	raw["version"] = CONFIG_VERSION
+
+	migrated, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, version, err
+	}
+
+	config, err := ParseConfig(migrated)
+	return config, version, err
+}
+
+// Backs up the configuration file as <path>.v<version> before saving the migrated one
+func backupConfig(path string, data []byte, version int) error {
+	path = os.ExpandEnv(path)
+	return ioutil.WriteFile(fmt.Sprintf("%s.v%d", path, version), data, 0600)
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	config, version, err := MigrateConfig([]byte("default: local\nhosts:\n- name: local\n  url: unix:///var/run/docker.sock\n"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if version != 0 {
+		t.Errorf("got %v\nwant %v", version, 0)
+	}
+	if config.Version != CONFIG_VERSION {
+		t.Errorf("got %v\nwant %v", config.Version, CONFIG_VERSION)
+	}
+	if (len(config.Hosts) != 1) || (config.Hosts[0].Name != "local") {
+		t.Errorf("got %v\nwant %v", config.Hosts, "local")
+	}
+
+	if _, _, err := MigrateConfig([]byte("version: 99\n")); err == nil {
+		t.Errorf("got %v\nwant %v", err, "an error for a newer version")
+	}
+}
+
+func TestLoadConfigMigrates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talk2docker")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	old := []byte("default: local\nhosts:\n- name: local\n  url: unix:///var/run/docker.sock\n")
+	ioutil.WriteFile(path, old, 0600)
+
+	if _, err := LoadConfig(path); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	backup, err := ioutil.ReadFile(path + ".v0")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(backup) != string(old) {
+		t.Errorf("got %v\nwant %v", string(backup), string(old))
+	}
+
+	config, version, err := MigrateConfig(mustReadFile(t, path))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if (version != CONFIG_VERSION) || (config.Default != "local") {
+		t.Errorf("got %v\nwant %v", version, CONFIG_VERSION)
+	}
+}
+
+func TestLoadConfigMigratesWhileLocked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talk2docker")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	ioutil.WriteFile(path, []byte("default: local\nhosts:\n- name: local\n  url: unix:///var/run/docker.sock\n"), 0600)
+
+	lock, err := LockConfig(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer lock.Unlock()
+
+	// It must not wait for the lock of its own
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if config.Version != CONFIG_VERSION {
+		t.Errorf("got %v\nwant %v", config.Version, CONFIG_VERSION)
+	}
+	if _, err := os.Stat(path + ".v0"); err != nil {
+		t.Errorf("got %v\nwant %v", err, "a backup")
+	}
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return data
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	DEFAULT_TAG = "latest"
+
+	MAX_NAME_LENGTH = 255
+)
+
+var (
+	reDomain        = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*|\[[0-9a-fA-F:.]+\])(?::[0-9]+)?$`)
+	rePathComponent = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*$`)
+	reTag           = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	reDigest        = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+
+	// Aliases of the Docker Hub
+	hubDomains = []string{"index.docker.io", "docker.io", "registry-1.docker.io"}
+)
+
+// An image reference in the form of [REGISTRY/]PATH[:TAG][@DIGEST]
+type Reference struct {
+	Registry string // "" for the Docker Hub
+	Path     string
+	Tag      string
+	Digest   string
+}
+
+func ParseReference(name string) (*Reference, error) {
+	if strings.Contains(name, "://") {
+		return nil, errors.New("Invalid reference with a schema")
+	}
+
+	if name == "" {
+		return nil, errors.New("Invalid reference: empty")
+	}
+
+	ref := &Reference{}
+	remainder := name
+
+	if n := strings.Index(remainder, "@"); n >= 0 {
+		ref.Digest = remainder[n+1:]
+		remainder = remainder[:n]
+		if !reDigest.MatchString(ref.Digest) {
+			return nil, fmt.Errorf("Invalid digest: %s", ref.Digest)
+		}
+	}
+
+	if n := strings.LastIndex(remainder, ":"); (n >= 0) && !strings.ContainsAny(remainder[n+1:], "/]") {
+		ref.Tag = remainder[n+1:]
+		remainder = remainder[:n]
+		if !reTag.MatchString(ref.Tag) {
+			return nil, fmt.Errorf("Invalid tag: %s", ref.Tag)
+		}
+	}
+
+	if (ref.Tag == "") && (ref.Digest == "") {
+		ref.Tag = DEFAULT_TAG
+	}
+
+	components := strings.SplitN(remainder, "/", 2)
+	if (len(components) == 2) && isDomain(components[0]) {
+		ref.Registry = components[0]
+		remainder = components[1]
+		if !reDomain.MatchString(ref.Registry) {
+			return nil, fmt.Errorf("Invalid registry: %s", ref.Registry)
+		}
+		for _, domain := range hubDomains {
+			if ref.Registry == domain {
+				ref.Registry = ""
+			}
+		}
+	}
+
+	if len(remainder) > MAX_NAME_LENGTH {
+		return nil, fmt.Errorf("Invalid repository name: longer than %d characters", MAX_NAME_LENGTH)
+	}
+
+	for _, component := range strings.Split(remainder, "/") {
+		if !rePathComponent.MatchString(component) {
+			if strings.ToLower(component) != component {
+				return nil, fmt.Errorf("Invalid repository name: %s, it must be lowercase", remainder)
+			}
+			return nil, fmt.Errorf("Invalid repository name: %s", remainder)
+		}
+	}
+	ref.Path = remainder
+
+	return ref, nil
+}
+
+func isDomain(component string) bool {
+	return strings.ContainsAny(component, ".:[") || (component == "localhost")
+}
+
+// Returns [REGISTRY/]PATH
+func (ref *Reference) Name() string {
+	if ref.Registry == "" {
+		return ref.Path
+	}
+	return ref.Registry + "/" + ref.Path
+}
+
+func (ref *Reference) String() string {
+	name := ref.Name()
+	if ref.Tag != "" {
+		name += ":" + ref.Tag
+	}
+	if ref.Digest != "" {
+		name += "@" + ref.Digest
+	}
+	return name
+}
+
+// Returns the digest if any, otherwise the tag
+func (ref *Reference) TagOrDigest() string {
+	if ref.Digest != "" {
+		return ref.Digest
+	}
+	return ref.Tag
+}
+
+// Parses the name as ParseReference, but with the default registry of the host
+// unless the name has a registry, including the Docker Hub
+func (host *Host) ParseReference(name string) (*Reference, error) {
+	ref, err := ParseReference(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if (ref.Registry == "") && (host.DefaultRegistry != "") {
+		if components := strings.SplitN(name, "/", 2); (len(components) < 2) || !isDomain(components[0]) {
+			ref.Registry = host.DefaultRegistry
+		}
+	}
+
+	return ref, nil
+}
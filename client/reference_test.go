@@ -0,0 +1,120 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDigest = "sha256:e58fcf7418d4390dec8e8fb69d88c06ec07039d651fedd3aa72af9972e7d046b"
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Reference
+		str      string
+	}{
+		{"busybox", Reference{"", "busybox", "latest", ""}, "busybox:latest"},
+		{"ailispaw/busybox", Reference{"", "ailispaw/busybox", "latest", ""}, "ailispaw/busybox:latest"},
+		{"ailispaw/busybox:latest", Reference{"", "ailispaw/busybox", "latest", ""}, "ailispaw/busybox:latest"},
+		{"ailispaw/busybox:tagname", Reference{"", "ailispaw/busybox", "tagname", ""}, "ailispaw/busybox:tagname"},
+		{"localhost/ailispaw/busybox:tagname", Reference{"localhost", "ailispaw/busybox", "tagname", ""}, "localhost/ailispaw/busybox:tagname"},
+		{"localhost:5000/ailispaw/busybox", Reference{"localhost:5000", "ailispaw/busybox", "latest", ""}, "localhost:5000/ailispaw/busybox:latest"},
+		{"localhost:5000/ailispaw/busybox:tagname", Reference{"localhost:5000", "ailispaw/busybox", "tagname", ""}, "localhost:5000/ailispaw/busybox:tagname"},
+		{"quay.io/flannel", Reference{"quay.io", "flannel", "latest", ""}, "quay.io/flannel:latest"},
+		{"192.168.33.201:5000/ailispaw/flannel", Reference{"192.168.33.201:5000", "ailispaw/flannel", "latest", ""}, "192.168.33.201:5000/ailispaw/flannel:latest"},
+		{"index.docker.io/busybox", Reference{"", "busybox", "latest", ""}, "busybox:latest"},
+		{"docker.io/library/busybox:1.0", Reference{"", "library/busybox", "1.0", ""}, "library/busybox:1.0"},
+		{"[::1]/busybox", Reference{"[::1]", "busybox", "latest", ""}, "[::1]/busybox:latest"},
+		{"[fe80::1]:5000/ailispaw/busybox:v1.0", Reference{"[fe80::1]:5000", "ailispaw/busybox", "v1.0", ""}, "[fe80::1]:5000/ailispaw/busybox:v1.0"},
+		{"busybox@" + testDigest, Reference{"", "busybox", "", testDigest}, "busybox@" + testDigest},
+		{"busybox:1.0@" + testDigest, Reference{"", "busybox", "1.0", testDigest}, "busybox:1.0@" + testDigest},
+		{"localhost:5000/busybox@" + testDigest, Reference{"localhost:5000", "busybox", "", testDigest}, "localhost:5000/busybox@" + testDigest},
+		{"my_app/my-app.v2__x", Reference{"", "my_app/my-app.v2__x", "latest", ""}, "my_app/my-app.v2__x:latest"},
+	}
+
+	for _, test := range tests {
+		ref, err := ParseReference(test.name)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if *ref != test.expected {
+			t.Errorf("%s: got %v\nwant %v", test.name, *ref, test.expected)
+		}
+		if ref.String() != test.str {
+			t.Errorf("%s: got %v\nwant %v", test.name, ref.String(), test.str)
+		}
+	}
+}
+
+func TestParseReferenceErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"https://index.docker.io/v1/busybox",
+		"Busybox",
+		"ailispaw/BusyBox:latest",
+		"localhost:5000/ailispaw/Busybox",
+		"busybox:",
+		"busybox:-tag",
+		"busybox:" + strings.Repeat("t", 129),
+		"busybox@sha256:1234",
+		"busybox@" + testDigest + "@" + testDigest,
+		"ailispaw//busybox",
+		"ailispaw/busybox-",
+		"-busybox",
+		"bad_host.com_/busybox",
+		strings.Repeat("a", MAX_NAME_LENGTH+1),
+	}
+
+	for _, name := range tests {
+		if ref, err := ParseReference(name); err == nil {
+			t.Errorf("%s: got %v\nwant an error", name, *ref)
+		}
+	}
+
+	if _, err := ParseReference("Busybox"); (err == nil) || !strings.Contains(err.Error(), "lowercase") {
+		t.Errorf("got %v\nwant %v", err, "an error about lowercase")
+	}
+}
+
+func TestReferenceName(t *testing.T) {
+	ref, err := ParseReference("localhost:5000/ailispaw/busybox:tagname")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if ref.Name() != "localhost:5000/ailispaw/busybox" {
+		t.Errorf("got %v\nwant %v", ref.Name(), "localhost:5000/ailispaw/busybox")
+	}
+	if ref.TagOrDigest() != "tagname" {
+		t.Errorf("got %v\nwant %v", ref.TagOrDigest(), "tagname")
+	}
+
+	ref, err = ParseReference("busybox:1.0@" + testDigest)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if ref.TagOrDigest() != testDigest {
+		t.Errorf("got %v\nwant %v", ref.TagOrDigest(), testDigest)
+	}
+}
+
+func TestHostParseReference(t *testing.T) {
+	host := &Host{Name: "local", DefaultRegistry: "registry.local:5000"}
+
+	tests := map[string]string{
+		"busybox":                           "registry.local:5000/busybox:latest",
+		"ailispaw/busybox:1.0":              "registry.local:5000/ailispaw/busybox:1.0",
+		"docker.io/busybox":                 "busybox:latest",
+		"localhost:5000/ailispaw/busybox:1": "localhost:5000/ailispaw/busybox:1",
+	}
+
+	for name, expected := range tests {
+		ref, err := host.ParseReference(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if ref.String() != expected {
+			t.Errorf("got %v\nwant %v", ref.String(), expected)
+		}
+	}
+}
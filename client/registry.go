@@ -1,9 +1,7 @@
 package client
 
 import (
-	"errors"
 	"fmt"
-	"strings"
 )
 
 func (config *Config) GetRegistry(reg string) (*Registry, error) {
@@ -40,35 +38,3 @@ func (config *Config) LogoutRegistry(reg string) {
 	}
 	return
 }
-
-func ParseRepositoryName(name string) (string, string, string, error) {
-	var (
-		reg = ""
-		tag = "latest"
-	)
-
-	if strings.Contains(name, "://") {
-		return "", "", "", errors.New("Invalid repository name with a schema")
-	}
-
-	if n := strings.LastIndex(name, ":"); n >= 0 {
-		if !strings.Contains(name[n+1:], "/") {
-			tag = name[n+1:]
-			name = name[:n]
-		}
-	}
-
-	names := strings.SplitN(name, "/", 2)
-	if (len(names) == 1) ||
-		(!strings.Contains(names[0], ".") && !strings.Contains(names[0], ":") && (names[0] != "localhost")) {
-		return "", name, tag, nil
-	}
-
-	reg = names[0]
-	name = names[1]
-	if strings.Contains(reg, "index.docker.io") {
-		return "", name, tag, nil
-	}
-
-	return reg, name, tag, nil
-}
@@ -0,0 +1,348 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+const (
+	INDEX_URL = "https://index.docker.io"
+
+	MANIFEST_V2 = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+var (
+	reAuthParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	reNextLink  = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+)
+
+// Talks to a Docker registry directly in the registry API v1 or v2
+type RegistryClient struct {
+	URL        *url.URL
+	HTTPClient *http.Client
+	Version    int
+
+	username string
+	password string
+	token    string
+}
+
+type RegistryError struct {
+	StatusCode int
+	Status     string
+	msg        string
+}
+
+func (e RegistryError) Error() string {
+	if e.msg == "" {
+		return fmt.Sprintf("Error response from registry: %s", e.Status)
+	}
+	return fmt.Sprintf("Error response from registry: %s", strings.TrimSpace(e.msg))
+}
+
+func NewRegistryClient(registry *Registry) (*RegistryClient, error) {
+	address := registry.Registry
+	if (address == "") || (address == INDEX_SERVER) {
+		address = INDEX_URL
+	}
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = ""
+
+	client := &RegistryClient{
+		URL:        u,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if registry.Credentials != "" {
+		authConfig, err := api.DecodeAuthConfig(registry.Credentials)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid credentials for %s: %s", registry.Registry, err)
+		}
+		client.username = authConfig.Username
+		client.password = authConfig.Password
+	}
+
+	if err := client.detectVersion(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (client *RegistryClient) detectVersion() error {
+	resp, err := client.HTTPClient.Get(client.URL.String() + "/v2/")
+	if err == nil {
+		resp.Body.Close()
+		if (resp.StatusCode == http.StatusOK) || (resp.StatusCode == http.StatusUnauthorized) ||
+			(resp.Header.Get("Docker-Distribution-API-Version") != "") {
+			client.Version = 2
+			return nil
+		}
+	}
+
+	resp, err = client.HTTPClient.Get(client.URL.String() + "/v1/_ping")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		client.Version = 1
+		return nil
+	}
+
+	return fmt.Errorf("%s doesn't seem to be a Docker registry", client.URL)
+}
+
+func (client *RegistryClient) doRequest(method, path string, headers map[string]string) ([]byte, http.Header, error) {
+	if !strings.Contains(path, "://") {
+		path = client.URL.String() + path
+	}
+
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		if client.token != "" {
+			req.Header.Set("Authorization", "Bearer "+client.token)
+		} else if client.username != "" {
+			req.SetBasicAuth(client.username, client.password)
+		}
+		log.Debugf("%s %s", method, path)
+		return client.HTTPClient.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get a token for the challenge and try again
+	if challenge := resp.Header.Get("WWW-Authenticate"); (resp.StatusCode == http.StatusUnauthorized) &&
+		strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		resp.Body.Close()
+		if err := client.getToken(challenge); err != nil {
+			return nil, nil, err
+		}
+		resp, err = send()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, nil, RegistryError{resp.StatusCode, resp.Status, string(bytes.TrimSpace(body))}
+	}
+
+	return body, resp.Header, nil
+}
+
+func (client *RegistryClient) getToken(challenge string) error {
+	params := map[string]string{}
+	for _, m := range reAuthParam.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+
+	realm, exists := params["realm"]
+	if !exists {
+		return errors.New("No realm in the authentication challenge")
+	}
+
+	v := url.Values{}
+	if service, exists := params["service"]; exists {
+		v.Set("service", service)
+	}
+	if scope, exists := params["scope"]; exists {
+		v.Set("scope", scope)
+	}
+	if client.username != "" {
+		v.Set("account", client.username)
+	}
+
+	req, err := http.NewRequest("GET", realm+"?"+v.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if client.username != "" {
+		req.SetBasicAuth(client.username, client.password)
+	}
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return RegistryError{resp.StatusCode, resp.Status, string(bytes.TrimSpace(body))}
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return err
+	}
+
+	client.token = token.Token
+	if client.token == "" {
+		client.token = token.AccessToken
+	}
+	if client.token == "" {
+		return errors.New("No token from the authorization server")
+	}
+	return nil
+}
+
+func (client *RegistryClient) ListRepositories() ([]string, error) {
+	var repositories []string
+
+	if client.Version == 1 {
+		body, _, err := client.doRequest("GET", "/v1/search?q=", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Results []struct {
+				Name string `json:"name"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, repository := range result.Results {
+			repositories = append(repositories, repository.Name)
+		}
+		sort.Strings(repositories)
+		return repositories, nil
+	}
+
+	for path := "/v2/_catalog"; path != ""; {
+		body, header, err := client.doRequest("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var catalog struct {
+			Repositories []string `json:"repositories"`
+		}
+		if err := json.Unmarshal(body, &catalog); err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, catalog.Repositories...)
+
+		path = ""
+		if m := reNextLink.FindStringSubmatch(header.Get("Link")); m != nil {
+			path = m[1]
+		}
+	}
+
+	return repositories, nil
+}
+
+func (client *RegistryClient) ListTags(name string) ([]string, error) {
+	var tags []string
+
+	if client.Version == 1 {
+		body, _, err := client.doRequest("GET", fmt.Sprintf("/v1/repositories/%s/tags", name), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Either {"tag": "image-id"} or [{"name": "tag", "layer": "image-id"}]
+		var byTag map[string]string
+		if err := json.Unmarshal(body, &byTag); err == nil {
+			for tag := range byTag {
+				tags = append(tags, tag)
+			}
+		} else {
+			var list []struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(body, &list); err != nil {
+				return nil, err
+			}
+			for _, tag := range list {
+				tags = append(tags, tag.Name)
+			}
+		}
+		sort.Strings(tags)
+		return tags, nil
+	}
+
+	body, _, err := client.doRequest("GET", fmt.Sprintf("/v2/%s/tags/list", name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, err
+	}
+	tags = list.Tags
+	sort.Strings(tags)
+	return tags, nil
+}
+
+func (client *RegistryClient) DeleteTag(name, tag string) error {
+	if client.Version == 1 {
+		_, _, err := client.doRequest("DELETE", fmt.Sprintf("/v1/repositories/%s/tags/%s", name, tag), nil)
+		return err
+	}
+
+	// Manifests can be deleted only by digest
+	_, header, err := client.doRequest("HEAD", fmt.Sprintf("/v2/%s/manifests/%s", name, tag),
+		map[string]string{"Accept": MANIFEST_V2})
+	if err != nil {
+		return err
+	}
+
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return fmt.Errorf("No digest for %s:%s", name, tag)
+	}
+
+	_, _, err = client.doRequest("DELETE", fmt.Sprintf("/v2/%s/manifests/%s", name, digest), nil)
+	if apiErr, ok := err.(RegistryError); ok && (apiErr.StatusCode == http.StatusMethodNotAllowed) {
+		return errors.New("The registry doesn't allow deletion")
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+func TestRegistryClientV2(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, ok := r.BasicAuth(); !ok || (user != "ailispaw") || (pass != "secret") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token":"abc"}`)
+			return
+		}
+
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="registry:catalog:*"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case (r.URL.Path == "/v2/_catalog") && (r.URL.Query().Get("last") == ""):
+			w.Header().Set("Link", `</v2/_catalog?last=app&n=1>; rel="next"`)
+			fmt.Fprint(w, `{"repositories":["app"]}`)
+		case r.URL.Path == "/v2/_catalog":
+			fmt.Fprint(w, `{"repositories":["db"]}`)
+		case r.URL.Path == "/v2/app/tags/list":
+			fmt.Fprint(w, `{"name":"app","tags":["v2","v1"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	authConfig := api.AuthConfig{
+		Username: "ailispaw",
+		Password: "secret",
+	}
+
+	registry, err := NewRegistryClient(&Registry{
+		Registry:    server.URL,
+		Credentials: authConfig.Encode(),
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if registry.Version != 2 {
+		t.Errorf("got %v\nwant %v", registry.Version, 2)
+	}
+
+	repositories, err := registry.ListRepositories()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if expected := []string{"app", "db"}; !reflect.DeepEqual(repositories, expected) {
+		t.Errorf("got %v\nwant %v", repositories, expected)
+	}
+
+	tags, err := registry.ListTags("app")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if expected := []string{"v1", "v2"}; !reflect.DeepEqual(tags, expected) {
+		t.Errorf("got %v\nwant %v", tags, expected)
+	}
+
+	if _, err := registry.ListTags("none"); err == nil {
+		t.Errorf("%v", "A missing repository should be an error.")
+	}
+}
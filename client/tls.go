@@ -3,9 +3,37 @@ package client
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
+	"fmt"
 	"io/ioutil"
 )
 
+const (
+	DEFAULT_TLS_MIN_VERSION = "1.0" // as before tls-min-version, for old daemons
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Returns the minimum TLS version of the host, or DEFAULT_TLS_MIN_VERSION if not specified
+func (host *Host) getTLSMinVersion() (uint16, error) {
+	name := host.TLSMinVersion
+	if name == "" {
+		name = DEFAULT_TLS_MIN_VERSION
+	}
+	version, ok := tlsVersions[name]
+	if !ok {
+		return 0, fmt.Errorf("Invalid TLS version: %s, it must be 1.0, 1.1, 1.2 or 1.3", name)
+	}
+	return version, nil
+}
+
+// Verifies the server with the CA certificate, or the system roots without it,
+// and presents a client certificate only if it's specified
 func (host *Host) getTLSConfig() (*tls.Config, error) {
 	var tlsConfig tls.Config
 
@@ -14,23 +42,36 @@ func (host *Host) getTLSConfig() (*tls.Config, error) {
 	}
 
 	tlsConfig.InsecureSkipVerify = !host.TLSVerify
+	tlsConfig.ServerName = host.TLSServerName
 
-	if host.TLSVerify {
+	if host.TLSVerify && (host.TLSCaCert != "") {
 		certPool := x509.NewCertPool()
 		file, err := ioutil.ReadFile(host.TLSCaCert)
 		if err != nil {
 			return nil, err
 		}
-		certPool.AppendCertsFromPEM(file)
+		if !certPool.AppendCertsFromPEM(file) {
+			return nil, fmt.Errorf("No certificates found in %s", host.TLSCaCert)
+		}
 		tlsConfig.RootCAs = certPool
 	}
 
-	cert, err := tls.LoadX509KeyPair(host.TLSCert, host.TLSKey)
+	if (host.TLSCert != "") || (host.TLSKey != "") {
+		if (host.TLSCert == "") || (host.TLSKey == "") {
+			return nil, errors.New("Needs both tls-cert and tls-key for a client certificate")
+		}
+		cert, err := tls.LoadX509KeyPair(host.TLSCert, host.TLSKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	minVersion, err := host.getTLSMinVersion()
 	if err != nil {
 		return nil, err
 	}
-	tlsConfig.Certificates = []tls.Certificate{cert}
-	tlsConfig.MinVersion = tls.VersionTLS10
+	tlsConfig.MinVersion = minVersion
 
 	return &tlsConfig, nil
 }
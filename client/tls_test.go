@@ -0,0 +1,43 @@
+package client
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestGetTLSConfig(t *testing.T) {
+	host := &Host{Name: "remote", URL: "tcp://10.0.0.1:2376", TLS: true, TLSVerify: true}
+
+	tlsConfig, err := host.getTLSConfig()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if tlsConfig.RootCAs != nil {
+		t.Errorf("got %v\nwant %v", tlsConfig.RootCAs, "the system roots")
+	}
+	if len(tlsConfig.Certificates) != 0 {
+		t.Errorf("got %v\nwant %v", tlsConfig.Certificates, "no client certificate")
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS10 {
+		t.Errorf("got %v\nwant %v", tlsConfig.MinVersion, tls.VersionTLS10)
+	}
+
+	host.TLSMinVersion = "1.3"
+	if tlsConfig, err = host.getTLSConfig(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("got %v\nwant %v", tlsConfig.MinVersion, tls.VersionTLS13)
+	}
+
+	host.TLSMinVersion = "1.4"
+	if _, err := host.getTLSConfig(); err == nil {
+		t.Errorf("got %v\nwant %v", err, "an error for an invalid version")
+	}
+
+	host.TLSMinVersion = ""
+	host.TLSCert = "cert.pem"
+	if _, err := host.getTLSConfig(); err == nil {
+		t.Errorf("got %v\nwant %v", err, "an error for a certificate without a key")
+	}
+}
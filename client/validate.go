@@ -0,0 +1,140 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Problems found in the configuration
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "Invalid configuration:\n  " + strings.Join(e, "\n  ")
+}
+
+func (config *Config) Validate() error {
+	var problems ValidationError
+
+	names := map[string]bool{}
+	for i, host := range config.Hosts {
+		if host.Name == "" {
+			problems = append(problems, fmt.Sprintf("hosts[%d]: no name", i))
+		} else if names[host.Name] {
+			problems = append(problems, fmt.Sprintf("hosts[%d]: duplicate name \"%s\"", i, host.Name))
+		}
+		names[host.Name] = true
+
+		if err := validateHostURL(host.URL); err != nil {
+			problems = append(problems, fmt.Sprintf("hosts[%d] %s: %s", i, host.Name, err))
+		}
+
+		for _, problem := range host.validateSettings() {
+			problems = append(problems, fmt.Sprintf("hosts[%d] %s: %s", i, host.Name, problem))
+		}
+
+		if host.TLS {
+			var files [][2]string
+			if (host.TLSCert != "") || (host.TLSKey != "") {
+				files = append(files, [2]string{"tls-cert", host.TLSCert}, [2]string{"tls-key", host.TLSKey})
+			}
+			if host.TLSVerify && (host.TLSCaCert != "") {
+				files = append(files, [2]string{"tls-ca-cert", host.TLSCaCert})
+			}
+			for _, file := range files {
+				if err := validateReadable(file[1]); err != nil {
+					problems = append(problems, fmt.Sprintf("hosts[%d] %s: %s: %s", i, host.Name, file[0], err))
+				}
+			}
+		}
+	}
+
+	if !names[config.Default] {
+		problems = append(problems, fmt.Sprintf("default: \"%s\" not found in the hosts", config.Default))
+	}
+
+	if _, err := config.ContextRules.WithDefaults(); err != nil {
+		problems = append(problems, fmt.Sprintf("context-rules: %s", err))
+	}
+
+	if (config.GC != nil) && (config.GC.OlderThan != "") {
+		if _, err := time.ParseDuration(config.GC.OlderThan); err != nil {
+			problems = append(problems, fmt.Sprintf("gc: older-than: %s", err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+var reApiVersion = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+
+// Checks the per-host settings, and returns the problems
+func (host *Host) validateSettings() []string {
+	var problems []string
+
+	if _, err := host.GetTimeout(); err != nil {
+		problems = append(problems, fmt.Sprintf("timeout: %s", err))
+	}
+	if _, err := host.getTLSMinVersion(); err != nil {
+		problems = append(problems, fmt.Sprintf("tls-min-version: %s", err))
+	}
+	if (host.ApiVersion != "") && !reApiVersion.MatchString(host.ApiVersion) {
+		problems = append(problems, fmt.Sprintf("api-version: invalid version %s, e.g. 1.16", host.ApiVersion))
+	}
+	if (host.DefaultRegistry != "") && !reDomain.MatchString(host.DefaultRegistry) {
+		problems = append(problems, fmt.Sprintf("default-registry: invalid registry %s", host.DefaultRegistry))
+	}
+	return problems
+}
+
+// Checks the per-host settings of the host to add or update
+func (host *Host) ValidateSettings() error {
+	if problems := host.validateSettings(); len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+func validateHostURL(rawurl string) error {
+	if rawurl == "" {
+		return fmt.Errorf("no url")
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+
+	switch u.Scheme {
+	case "unix":
+		if u.Path == "" {
+			return fmt.Errorf("no socket path in %s", rawurl)
+		}
+	case "tcp", "http", "https":
+		if u.Host == "" {
+			return fmt.Errorf("no host in %s", rawurl)
+		}
+	default:
+		return fmt.Errorf("unsupported scheme in %s, it must be unix, tcp, http or https", rawurl)
+	}
+	return nil
+}
+
+func validateReadable(path string) error {
+	if path == "" {
+		return fmt.Errorf("not specified")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talk2docker")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cert := filepath.Join(dir, "cert.pem")
+	ioutil.WriteFile(cert, []byte{}, 0600)
+
+	config := &Config{
+		Default: "local",
+		Hosts: []Host{
+			{Name: "local", URL: "unix:///var/run/docker.sock"},
+			{Name: "remote", URL: "tcp://10.0.0.1:2376", TLS: true, TLSCert: cert, TLSKey: cert},
+			{Name: "pinned", URL: "tcp://10.0.0.2:2376", Timeout: "10s", ApiVersion: "1.16", DefaultRegistry: "registry.local:5000"},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("got %v\nwant %v", err, nil)
+	}
+
+	config = &Config{
+		Default: "missing",
+		Hosts: []Host{
+			{Name: "local", URL: "unix://"},
+			{Name: "local", URL: "ftp://10.0.0.1"},
+			{Name: "remote", URL: "tcp://10.0.0.1:2376", TLS: true, TLSCert: cert},
+			{Name: "pinned", URL: "tcp://10.0.0.2:2376", Timeout: "10", ApiVersion: "v1.16", DefaultRegistry: "registry.local:5000"},
+			{Name: "zero", URL: "tcp://10.0.0.3:2376", Timeout: "0s"},
+			{Name: "negative", URL: "tcp://10.0.0.4:2376", Timeout: "-5s"},
+		},
+		GC: &GCRules{OlderThan: "a week"},
+	}
+	err = config.Validate()
+	problems, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("got %v\nwant %v", err, "ValidationError")
+	}
+	if len(problems) != 10 {
+		t.Errorf("got %v\nwant %v", problems, "10 problems")
+	}
+}
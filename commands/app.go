@@ -6,6 +6,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/ailispaw/talk2docker/api"
 	"github.com/ailispaw/talk2docker/version"
 )
 
@@ -16,6 +17,8 @@ const (
 var (
 	configPath string
 	hostName   string
+	progress   string
+	format     string
 
 	boolYAML, boolJSON, boolVerbose, boolDebug, boolVersion bool
 
@@ -48,6 +51,12 @@ func init() {
 	app.PersistentFlags().BoolVarP(&boolYAML, "yaml", "Y", false, "Output in YAML format")
 	app.PersistentFlags().BoolVarP(&boolJSON, "json", "J", false, "Output in JSON format")
 
+	app.PersistentFlags().StringVar(&format, "format", "", "Output each item with a Go template, e.g. '{{.Id}} {{size .VirtualSize}}'")
+
+	app.PersistentFlags().StringVarP(&output, "output", "o", OUTPUT_TABLE, "Output tables in the format: table, csv or tsv")
+
+	app.PersistentFlags().StringVar(&progress, "progress", api.PROGRESS_AUTO, "Progress output: auto, tty, plain or json")
+
 	app.PersistentFlags().BoolVarP(&boolVerbose, "verbose", "V", false, "Print verbose messages")
 	app.PersistentFlags().BoolVarP(&boolDebug, "debug", "D", false, "Print debug messages")
 
@@ -68,6 +77,15 @@ func Initialize() {
 		log.SetFormatter(&log.TextFormatter{})
 		log.SetLevel(log.DebugLevel)
 	}
+
+	if err := validateOutput(output); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := api.GetProgressRendererFactory(progress); err != nil {
+		log.Fatal(err)
+	}
+	api.DefaultProgressMode = progress
 }
 
 func Execute() {
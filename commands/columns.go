@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/client"
+)
+
+const (
+	OUTPUT_TABLE = "table"
+	OUTPUT_CSV   = "csv"
+	OUTPUT_TSV   = "tsv"
+)
+
+var (
+	output      string
+	columnNames []string
+	boolWide    bool
+
+	display *client.Display
+)
+
+// A column of a table, declared once per command
+type Column struct {
+	Name   string // for --columns
+	Header string
+	Wide   bool // shown only with --wide unless selected by --columns
+	Value  func(i int) string
+}
+
+func validateOutput(output string) error {
+	switch output {
+	case OUTPUT_TABLE, OUTPUT_CSV, OUTPUT_TSV:
+		return nil
+	}
+	return fmt.Errorf("Invalid output: %s (table, csv or tsv)", output)
+}
+
+// Returns the columns chosen by names in their order, or the default ones
+func selectColumns(columns []Column, names []string, wide bool) ([]Column, error) {
+	if len(names) == 0 {
+		selected := []Column{}
+		for _, column := range columns {
+			if wide || !column.Wide {
+				selected = append(selected, column)
+			}
+		}
+		return selected, nil
+	}
+
+	byName := map[string]Column{}
+	available := []string{}
+	for _, column := range columns {
+		byName[column.Name] = column
+		available = append(available, column.Name)
+	}
+
+	selected := []Column{}
+	for _, name := range names {
+		column, exists := byName[strings.ToLower(strings.TrimSpace(name))]
+		if !exists {
+			return nil, fmt.Errorf("Invalid column: %s, available columns are %s", name, strings.Join(available, ", "))
+		}
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
+
+func hasColumn(columns []Column, name string) bool {
+	for _, column := range columns {
+		if column.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Renders n rows of the columns in a table, CSV or TSV by --output
+func PrintColumns(out io.Writer, columns []Column, n int) {
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, column.Header)
+	}
+
+	var items [][]string
+	for i := 0; i < n; i++ {
+		row := []string{}
+		for _, column := range columns {
+			row = append(row, column.Value(i))
+		}
+		items = append(items, row)
+	}
+
+	PrintInTable(out, header, items, 0, tablewriter.ALIGN_DEFAULT)
+}
+
+func PrintInCSV(out io.Writer, header []string, items [][]string, comma rune) error {
+	writer := csv.NewWriter(out)
+	writer.Comma = comma
+
+	plain := func(row []string) []string {
+		result := []string{}
+		for _, s := range row {
+			result = append(result, strings.Replace(s, " ", " ", -1))
+		}
+		return result
+	}
+
+	if !boolNoHeader && (header != nil) {
+		if err := writer.Write(plain(header)); err != nil {
+			return err
+		}
+	}
+	for _, row := range items {
+		if err := writer.Write(plain(row)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Returns the display options in the configuration file, loaded once
+func getDisplay() *client.Display {
+	if display == nil {
+		display = &client.Display{}
+		if config, err := client.LoadConfig(configPath); err == nil && (config.Display != nil) {
+			display = config.Display
+		}
+	}
+	return display
+}
+
+func getSizeHeader() string {
+	if getDisplay().HumanUnits {
+		return "Size"
+	}
+	return "Size(MB)"
+}
+
+// Formats bytes in MB, or in human units by the configuration
+func FormatSizeValue(n int64) string {
+	if getDisplay().HumanUnits {
+		return FormatSize(float64(n))
+	}
+	return FormatFloat(float64(n) / 1000000)
+}
+
+// Formats a time in absolute, or relative by the configuration
+func FormatTimeValue(t time.Time) string {
+	if getDisplay().RelativeTime {
+		return FormatSince(t, time.Now())
+	}
+	return FormatDateTime(t)
+}
+
+func FormatLabels(labels map[string]string) string {
+	list := []string{}
+	for key, value := range labels {
+		list = append(list, key+"="+value)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
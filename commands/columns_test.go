@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSelectColumns(t *testing.T) {
+	columns := []Column{
+		{"id", "ID", false, nil},
+		{"name", "Name", false, nil},
+		{"ip", "IP Address", true, nil},
+	}
+
+	names := func(columns []Column) string {
+		result := ""
+		for _, column := range columns {
+			result += column.Name + " "
+		}
+		return result
+	}
+
+	tests := []struct {
+		names    []string
+		wide     bool
+		expected string
+	}{
+		{nil, false, "id name "},
+		{nil, true, "id name ip "},
+		{[]string{"ip", "ID"}, false, "ip id "},
+	}
+
+	for _, test := range tests {
+		selected, err := selectColumns(columns, test.names, test.wide)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if names(selected) != test.expected {
+			t.Errorf("%v: got %v\nwant %v", test.names, names(selected), test.expected)
+		}
+	}
+
+	if _, err := selectColumns(columns, []string{"size"}, false); err == nil {
+		t.Errorf("%v", "An unknown column should be an error.")
+	}
+}
+
+func TestPrintColumnsInCSV(t *testing.T) {
+	defer func() { output = OUTPUT_TABLE }()
+
+	rows := [][]string{
+		{"8c2e06607696", "busybox:latest, busybox:1"},
+		{"4986bf8c1536", FormatNonBreakingString("<none> \"x\"")},
+	}
+	columns := []Column{
+		{"id", "ID", false, func(i int) string { return rows[i][0] }},
+		{"name", "Name:Tags", false, func(i int) string { return rows[i][1] }},
+	}
+
+	tests := map[string]string{
+		OUTPUT_CSV: "ID,Name:Tags\n8c2e06607696,\"busybox:latest, busybox:1\"\n4986bf8c1536,\"<none> \"\"x\"\"\"\n",
+		OUTPUT_TSV: "ID\tName:Tags\n8c2e06607696\tbusybox:latest, busybox:1\n4986bf8c1536\t\"<none> \"\"x\"\"\"\n",
+	}
+
+	for mode, expected := range tests {
+		output = mode
+		out := new(bytes.Buffer)
+		PrintColumns(out, columns, len(rows))
+		if out.String() != expected {
+			t.Errorf("%s: got %v\nwant %v", mode, out.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+type ImageConfigChange struct {
+	Field string
+	A     string
+	B     string
+}
+
+type ImageLayer struct {
+	ID        string
+	CreatedBy string
+	Size      int64
+}
+
+type ImageComparison struct {
+	A      string
+	B      string
+	Config []ImageConfigChange
+	Shared []ImageLayer
+	OnlyA  []ImageLayer
+	OnlyB  []ImageLayer
+}
+
+var cmdDiffImages = &cobra.Command{
+	Use:   "diff <NAME[:TAG]|ID> <NAME[:TAG]|ID>",
+	Short: "Compare two images",
+	Long:  APP_NAME + " image diff - Compare two images",
+	Run:   diffImages,
+}
+
+func init() {
+	flags := cmdDiffImages.Flags()
+	flags.BoolVarP(&boolAll, "all", "a", false, "Show the whole build instructions")
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdImage.AddCommand(cmdDiffImages)
+}
+
+func diffImages(ctx *cobra.Command, args []string) {
+	if len(args) < 2 {
+		ErrorExit(ctx, "Needs two arguments <NAME[:TAG]|ID> <NAME[:TAG]|ID> to compare")
+	}
+
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var (
+		infos     [2]*api.ImageInfo
+		histories [2]api.ImageHistories
+	)
+	for i, name := range args[:2] {
+		if infos[i], err = docker.InspectImage(name); err != nil {
+			log.Fatal(err)
+		}
+		if histories[i], err = docker.GetImageHistory(name); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	comparison := ImageComparison{
+		A:      args[0],
+		B:      args[1],
+		Config: compareImageConfigs(&infos[0].Config, &infos[1].Config),
+	}
+	comparison.Shared, comparison.OnlyA, comparison.OnlyB = compareImageHistories(histories[0], histories[1])
+
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), comparison); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(comparison.Config) == 0 {
+		ctx.Println("The configs are identical")
+	} else {
+		var items [][]string
+		for _, change := range comparison.Config {
+			out := []string{
+				change.Field,
+				change.A,
+				change.B,
+			}
+			items = append(items, out)
+		}
+
+		header := []string{
+			"Field",
+			comparison.A,
+			comparison.B,
+		}
+
+		PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+	}
+
+	var items [][]string
+
+	if len(comparison.Shared) > 0 {
+		var size int64
+		for _, layer := range comparison.Shared {
+			size += layer.Size
+		}
+		top := comparison.Shared[len(comparison.Shared)-1]
+		items = append(items, []string{
+			"shared",
+			Truncate(top.ID, 12),
+			fmt.Sprintf("%d layer(s) of the base", len(comparison.Shared)),
+			FormatFloat(float64(size) / 1000000),
+		})
+	}
+
+	for _, side := range []struct {
+		name   string
+		layers []ImageLayer
+	}{
+		{comparison.A, comparison.OnlyA},
+		{comparison.B, comparison.OnlyB},
+	} {
+		for _, layer := range side.layers {
+			createdBy := formatCreatedBy(layer.CreatedBy)
+			if !boolAll {
+				createdBy = FormatNonBreakingString(Truncate(createdBy, 50))
+			}
+			items = append(items, []string{
+				side.name,
+				Truncate(layer.ID, 12),
+				createdBy,
+				FormatFloat(float64(layer.Size) / 1000000),
+			})
+		}
+	}
+
+	header := []string{
+		"Image",
+		"ID",
+		"Created by",
+		"Size(MB)",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 20, tablewriter.ALIGN_DEFAULT)
+}
+
+func compareImageConfigs(a, b *api.Config) []ImageConfigChange {
+	formatList := func(list []string) string {
+		if len(list) == 0 {
+			return ""
+		}
+		data, err := json.Marshal(list)
+		if err != nil {
+			return strings.Join(list, " ")
+		}
+		return string(data)
+	}
+
+	formatSet := func(set map[string]struct{}) string {
+		var keys []string
+		for key := range set {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ", ")
+	}
+
+	formatEnv := func(env []string) string {
+		sorted := append([]string{}, env...)
+		sort.Strings(sorted)
+		return strings.Join(sorted, ", ")
+	}
+
+	fields := []ImageConfigChange{
+		{"Env", formatEnv(a.Env), formatEnv(b.Env)},
+		{"Cmd", formatList(a.Cmd), formatList(b.Cmd)},
+		{"Entrypoint", formatList(a.Entrypoint), formatList(b.Entrypoint)},
+		{"ExposedPorts", formatSet(a.ExposedPorts), formatSet(b.ExposedPorts)},
+		{"Volumes", formatSet(a.Volumes), formatSet(b.Volumes)},
+		{"User", a.User, b.User},
+		{"WorkingDir", a.WorkingDir, b.WorkingDir},
+		{"OnBuild", formatList(a.OnBuild), formatList(b.OnBuild)},
+	}
+
+	var changes []ImageConfigChange
+	for _, field := range fields {
+		if field.A != field.B {
+			changes = append(changes, field)
+		}
+	}
+	return changes
+}
+
+// Returns the shared layers and the diverging layers of each image, from the oldest
+func compareImageHistories(a, b api.ImageHistories) ([]ImageLayer, []ImageLayer, []ImageLayer) {
+	toLayers := func(history api.ImageHistories) []ImageLayer {
+		var layers []ImageLayer
+		for i := len(history) - 1; i >= 0; i-- {
+			layers = append(layers, ImageLayer{
+				ID:        history[i].Id,
+				CreatedBy: history[i].CreatedBy,
+				Size:      history[i].Size,
+			})
+		}
+		return layers
+	}
+
+	layersA, layersB := toLayers(a), toLayers(b)
+
+	n := 0
+	for (n < len(layersA)) && (n < len(layersB)) && (layersA[n].ID == layersB[n].ID) {
+		n++
+	}
+
+	return layersA[:n], layersA[n:], layersB[n:]
+}
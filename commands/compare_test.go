@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+func TestCompareImageHistories(t *testing.T) {
+	a := api.ImageHistories{{Id: "a2"}, {Id: "a1"}, {Id: "base1"}, {Id: "base0"}}
+	b := api.ImageHistories{{Id: "b1"}, {Id: "base1"}, {Id: "base0"}}
+
+	shared, onlyA, onlyB := compareImageHistories(a, b)
+	if (len(shared) != 2) || (shared[1].ID != "base1") {
+		t.Errorf("got %v\nwant %v", shared, "base0, base1")
+	}
+	if (len(onlyA) != 2) || (onlyA[0].ID != "a1") {
+		t.Errorf("got %v\nwant %v", onlyA, "a1, a2")
+	}
+	if (len(onlyB) != 1) || (onlyB[0].ID != "b1") {
+		t.Errorf("got %v\nwant %v", onlyB, "b1")
+	}
+}
+
+func TestCompareImageConfigs(t *testing.T) {
+	changes := compareImageConfigs(
+		&api.Config{Env: []string{"A=1", "B=2"}, User: "app", Cmd: []string{"run"}},
+		&api.Config{Env: []string{"B=2", "A=1"}, User: "root", Cmd: []string{"run"}})
+	if (len(changes) != 1) || (changes[0].Field != "User") {
+		t.Errorf("got %v\nwant %v", changes, "User only")
+	}
+}
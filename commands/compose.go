@@ -111,6 +111,8 @@ func init() {
 		flags.StringVar(&composeFlags.RestartPolicy, "restart", "", "Restart policy to apply when a container exits (no, on-failure[:MAX-RETRY], always)")
 		flags.StringSliceVar(&composeFlags.SecurityOpt, "security-opt", nil, "Security options")
 		flags.BoolVar(&composeFlags.ReadonlyRootfs, "read-only", false, "Mount the container's root filesystem as read only")
+
+		flags.BoolVarP(&boolForce, "force", "f", false, "Build images even if their build contexts have not changed")
 	}
 
 	cmdContainer.AddCommand(cmdComposeContainers)
@@ -232,14 +234,11 @@ func composeContainer(ctx *cobra.Command, root string, composer Composer) (strin
 	)
 
 	if composer.Image != "" {
-		r, n, t, err := client.ParseRepositoryName(composer.Image)
+		ref, err := parseReference(composer.Image)
 		if err != nil {
 			return "", err
 		}
-		composer.Image = n + ":" + t
-		if r != "" {
-			composer.Image = r + "/" + composer.Image
-		}
+		composer.Image = ref.String()
 	}
 
 	if (composer.WorkingDir != "") && !filepath.IsAbs(composer.WorkingDir) {
@@ -255,7 +254,7 @@ func composeContainer(ctx *cobra.Command, root string, composer Composer) (strin
 		if !filepath.IsAbs(composer.Build) {
 			composer.Build = filepath.Join(root, composer.Build)
 		}
-		message, err := docker.BuildImage(composer.Build, composer.Image, false)
+		message, err := buildImageIfChanged(ctx, docker, composer.Build, composer.Image, false, boolForce)
 		if err != nil {
 			return "", err
 		}
@@ -444,7 +443,12 @@ func composeContainer(ctx *cobra.Command, root string, composer Composer) (strin
 	cid, err = docker.CreateContainer(composer.Name, config, hostConfig)
 	if err != nil {
 		if apiErr, ok := err.(api.Error); ok && (apiErr.StatusCode == 404) {
-			if _, err := docker.PullImage(config.Image); err != nil {
+			credentials, err := getRegistryCredentials(config.Image)
+			if err != nil {
+				return "", err
+			}
+
+			if _, err := docker.PullImage(config.Image, credentials); err != nil {
 				return "", err
 			}
 
@@ -1,11 +1,13 @@
 package commands
 
 import (
+	"io/ioutil"
 	"os"
 	"os/exec"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 
 	"github.com/ailispaw/talk2docker/client"
 )
@@ -36,10 +38,39 @@ var cmdEditConfig = &cobra.Command{
 	Run:     editConfig,
 }
 
+var cmdMigrateConfig = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the configuration file to the current version",
+	Long:  APP_NAME + " config migrate - Upgrade the configuration file to the current version",
+	Run:   migrateConfig,
+}
+
+var cmdValidateConfig = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration file",
+	Long:  APP_NAME + " config validate - Validate the configuration file",
+	Run:   validateConfig,
+}
+
 func init() {
 	cmdConfig.AddCommand(cmdCatConfig)
 
 	cmdConfig.AddCommand(cmdEditConfig)
+
+	cmdConfig.AddCommand(cmdValidateConfig)
+
+	flags := cmdMigrateConfig.Flags()
+	flags.BoolVar(&boolDryRun, "dry-run", false, "Only show the changes")
+	cmdConfig.AddCommand(cmdMigrateConfig)
+}
+
+// Locks the configuration file to modify it, which is unlocked at exit anyway
+func lockConfig() *client.ConfigLock {
+	lock, err := client.LockConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return lock
 }
 
 func catConfig(ctx *cobra.Command, args []string) {
@@ -54,19 +85,117 @@ func catConfig(ctx *cobra.Command, args []string) {
 }
 
 func editConfig(ctx *cobra.Command, args []string) {
+	defer lockConfig().Unlock()
+
 	path := os.ExpandEnv(configPath)
 
+	if _, err := client.UpgradeConfig(configPath); err != nil {
+		log.Warn(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Edit a copy, which replaces the configuration file only when it is valid
+	editPath := path + ".edit"
+	if err := ioutil.WriteFile(editPath, data, 0600); err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(editPath)
+
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "vi"
 	}
 
-	cmd := exec.Command(editor, path)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err := cmd.Run()
+	for {
+		cmd := exec.Command(editor, editPath)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			log.Fatal(err)
+		}
+
+		data, err = ioutil.ReadFile(editPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		config, _, err := client.MigrateConfig(data)
+		if err == nil {
+			err = config.Validate()
+		}
+		if err == nil {
+			break
+		}
+
+		log.Error(err)
+		if !confirm(ctx, "Re-open the editor?") {
+			os.Remove(editPath)
+			log.Fatal("The configuration file is not changed.")
+		}
+	}
+
+	if err := client.WriteConfig(configPath, data); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func validateConfig(ctx *cobra.Command, args []string) {
+	data, err := ioutil.ReadFile(os.ExpandEnv(configPath))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	config, _, err := client.MigrateConfig(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := config.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx.Println("The configuration file is valid.")
+}
+
+func migrateConfig(ctx *cobra.Command, args []string) {
+	defer lockConfig().Unlock()
+
+	data, err := ioutil.ReadFile(os.ExpandEnv(configPath))
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	config, version, err := client.MigrateConfig(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if version == client.CONFIG_VERSION {
+		ctx.Printf("The configuration file is already version %d.\n", version)
+		return
+	}
+
+	migrated, err := yaml.Marshal(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, line := range DiffLines(string(data), string(migrated)) {
+		ctx.Println(line)
+	}
+
+	if boolDryRun {
+		return
+	}
+
+	if _, err := client.UpgradeConfig(configPath); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx.Printf("Migrated the configuration file from version %d to %d.\n", version, client.CONFIG_VERSION)
 }
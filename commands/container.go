@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -167,6 +168,13 @@ func init() {
 		flags.BoolVarP(&boolQuiet, "quiet", "q", false, "Only display numeric IDs")
 		flags.BoolVarP(&boolSize, "size", "s", false, "Display sizes")
 		flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+		flags.StringSliceVar(&filterArgs, "filter", []string{}, "Filter output based on key=value (status, exited, name, label)")
+		flags.StringVar(&since, "since", "", "Show only containers created since <NAME|ID>, include non-running ones.")
+		flags.StringVar(&before, "before", "", "Show only containers created before <NAME|ID>, include non-running ones.")
+		flags.StringVar(&sortBy, "sort", "", "Sort by created, size, name or status")
+		flags.StringSliceVar(&columnNames, "columns", []string{}, "Columns to show: id, names, image, command, created, status, ports, size, ip, restarts, labels")
+		flags.BoolVarP(&boolWide, "wide", "w", false, "Show extra columns: ip, restarts and labels")
+		addMultiHostFlags(flags)
 	}
 
 	cmdContainer.AddCommand(cmdListContainers)
@@ -219,8 +227,14 @@ func init() {
 	cmdContainer.AddCommand(cmdUploadToContainer)
 }
 
+// A container with the host where it runs, for --all-hosts and --hosts
+type HostContainer struct {
+	Host          string
+	api.Container `yaml:",inline"`
+}
+
 func listContainers(ctx *cobra.Command, args []string) {
-	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	hosts, err := getTargetHosts()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -230,30 +244,31 @@ func listContainers(ctx *cobra.Command, args []string) {
 		limit = 1
 	}
 
-	containers, err := docker.ListContainers(boolAll, boolSize, limit, "", "", nil)
+	daemonFilters, clientFilters, err := parseFilters(filterArgs, daemonContainerFilters, clientContainerFilters)
 	if err != nil {
-		log.Fatal(err)
+		ErrorExit(ctx, err.Error())
 	}
 
-	if boolQuiet {
-		for _, container := range containers {
-			ctx.Println(Truncate(container.Id, 12))
-		}
-		return
+	if err := validateSortField(sortBy, SORT_CREATED, SORT_SIZE, SORT_NAME, SORT_STATUS); err != nil {
+		ErrorExit(ctx, err.Error())
 	}
 
-	if boolYAML || boolJSON {
-		if err := FormatPrint(ctx.Out(), containers); err != nil {
-			log.Fatal(err)
-		}
-		return
-	}
+	var (
+		rows    []HostContainer
+		dockers = map[string]*api.DockerClient{}
+		infos   = map[string]*api.ContainerInfo{}
+	)
 
-	trimNamePrefix := func(ss []string) []string {
-		for i, s := range ss {
-			ss[i] = strings.TrimPrefix(s, "/")
+	getInfo := func(i int) *api.ContainerInfo {
+		key := rows[i].Host + "/" + rows[i].Id
+		if _, exists := infos[key]; !exists {
+			info, err := dockers[rows[i].Host].InspectContainer(rows[i].Id)
+			if err != nil {
+				log.Warn(err)
+			}
+			infos[key] = info
 		}
-		return ss
+		return infos[key]
 	}
 
 	formatPorts := func(ports []api.Port) string {
@@ -269,37 +284,159 @@ func listContainers(ctx *cobra.Command, args []string) {
 		return strings.Join(result, ", ")
 	}
 
-	var items [][]string
-	for _, container := range containers {
-		out := []string{
-			Truncate(container.Id, 12),
-			strings.Join(trimNamePrefix(container.Names), ", "),
-			container.Image,
-			Truncate(container.Command, 30),
-			FormatDateTime(time.Unix(container.Created, 0)),
-			container.Status,
-			formatPorts(container.Ports),
+	available := []Column{
+		{"id", "ID", false, func(i int) string {
+			return Truncate(rows[i].Id, 12)
+		}},
+		{"names", "Names", false, func(i int) string {
+			names := []string{}
+			for _, name := range rows[i].Names {
+				names = append(names, strings.TrimPrefix(name, "/"))
+			}
+			return strings.Join(names, ", ")
+		}},
+		{"image", "Image", false, func(i int) string {
+			return rows[i].Image
+		}},
+		{"command", "Command", false, func(i int) string {
+			return Truncate(rows[i].Command, 30)
+		}},
+		{"created", "Created at", false, func(i int) string {
+			return FormatTimeValue(time.Unix(rows[i].Created, 0))
+		}},
+		{"status", "Status", false, func(i int) string {
+			return rows[i].Status
+		}},
+		{"ports", "Ports", false, func(i int) string {
+			return formatPorts(rows[i].Ports)
+		}},
+		{"size", getSizeHeader(), false, func(i int) string {
+			return FormatSizeValue(rows[i].SizeRw)
+		}},
+		{"ip", "IP Address", true, func(i int) string {
+			if info := getInfo(i); info != nil {
+				return info.NetworkSettings.IPAddress
+			}
+			return ""
+		}},
+		{"restarts", "Restarts", true, func(i int) string {
+			if info := getInfo(i); info != nil {
+				return strconv.Itoa(info.RestartCount)
+			}
+			return ""
+		}},
+		{"labels", "Labels", true, func(i int) string {
+			if info := getInfo(i); info != nil {
+				return FormatLabels(info.Config.Labels)
+			}
+			return ""
+		}},
+	}
+
+	// The size column is only with --size or --columns size, since it is expensive
+	if !boolSize && (len(columnNames) == 0) {
+		for i, column := range available {
+			if column.Name == "size" {
+				available = append(available[:i], available[i+1:]...)
+				break
+			}
+		}
+	}
+
+	columns, err := selectColumns(available, columnNames, boolWide)
+	if err != nil {
+		ErrorExit(ctx, err.Error())
+	}
+
+	size := boolSize || (sortBy == SORT_SIZE) || hasColumn(columns, "size")
+
+	type hostContainers struct {
+		docker     *api.DockerClient
+		containers []api.Container
+	}
+
+	getContainers := func(name string) (interface{}, error) {
+		docker, err := client.NewDockerClient(configPath, name, ctx.Out())
+		if err != nil {
+			return nil, err
+		}
+
+		containers, err := docker.ListContainers(boolAll, size, limit, since, before, daemonFilters)
+		if err != nil {
+			return nil, err
+		}
+
+		containers, err = filterContainers(docker, containers, clientFilters)
+		if err != nil {
+			return nil, err
+		}
+
+		return hostContainers{docker, containers}, nil
+	}
+
+	var results []HostResult
+	if hosts == nil {
+		value, err := getContainers(hostName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		results = append(results, HostResult{hostName, value, nil})
+	} else {
+		results = forEachHost(hosts, getContainers)
+	}
+
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		value := result.Value.(hostContainers)
+		dockers[result.Host] = value.docker
+		for _, container := range value.containers {
+			rows = append(rows, HostContainer{result.Host, container})
+		}
+	}
+
+	sortContainers(rows, sortBy)
+
+	defer func() {
+		if err := reportHostErrors(results); err != nil {
+			log.Fatal(err)
 		}
-		if boolSize {
-			out = append(out, FormatFloat(float64(container.SizeRw)/1000000))
+	}()
+
+	if boolQuiet {
+		for _, row := range rows {
+			if hosts == nil {
+				ctx.Println(Truncate(row.Id, 12))
+			} else {
+				ctx.Printf("%s\t%s\n", row.Host, Truncate(row.Id, 12))
+			}
 		}
-		items = append(items, out)
+		return
 	}
 
-	header := []string{
-		"ID",
-		"Names",
-		"Image",
-		"Command",
-		"Created at",
-		"Status",
-		"Ports",
+	if boolYAML || boolJSON || (format != "") {
+		var items interface{} = rows
+		if hosts == nil {
+			containers := []api.Container{}
+			for _, row := range rows {
+				containers = append(containers, row.Container)
+			}
+			items = containers
+		}
+		if err := FormatPrint(ctx.Out(), items); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
-	if boolSize {
-		header = append(header, "Size(MB)")
+
+	if hosts != nil {
+		columns = append([]Column{{"host", "Host", false, func(i int) string {
+			return rows[i].Host
+		}}}, columns...)
 	}
 
-	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+	PrintColumns(ctx.Out(), columns, len(rows))
 }
 
 func inspectContainers(ctx *cobra.Command, args []string) {
@@ -620,7 +757,7 @@ func getContainerProcesses(ctx *cobra.Command, args []string) {
 		log.Fatal(err)
 	}
 
-	if boolYAML || boolJSON {
+	if boolYAML || boolJSON || (format != "") {
 		if err := FormatPrint(ctx.Out(), ps); err != nil {
 			log.Fatal(err)
 		}
@@ -635,15 +772,17 @@ func commitContainer(ctx *cobra.Command, args []string) {
 		ErrorExit(ctx, "Needs two arguments to commit <CONTAINER-NAME|ID> to <IMAGE-NAME[:TAG]>")
 	}
 
-	reg, name, tag, err := client.ParseRepositoryName(args[1])
+	ref, err := parseReference(args[1])
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if reg != "" {
-		name = reg + "/" + name
+	if ref.Digest != "" {
+		log.Fatalf("Needs a tag, not a digest, to commit as: %s", args[1])
 	}
 
+	name, tag := ref.Name(), ref.Tag
+
 	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
 	if err != nil {
 		log.Fatal(err)
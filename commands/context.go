@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"path/filepath"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+const (
+	CONTEXT_TOP_ENTRIES = 10
+)
+
+var (
+	boolAnalyzeContext bool
+)
+
+type ContextEntry struct {
+	Path  string
+	Type  string
+	Files int
+	Size  int64
+}
+
+type ContextEntries []ContextEntry
+
+func (entries ContextEntries) Len() int {
+	return len(entries)
+}
+
+func (entries ContextEntries) Swap(i, j int) {
+	entries[i], entries[j] = entries[j], entries[i]
+}
+
+func (entries ContextEntries) Less(i, j int) bool {
+	if entries[i].Size == entries[j].Size {
+		return entries[i].Path > entries[j].Path
+	}
+	return entries[i].Size < entries[j].Size
+}
+
+type ContextAnalysis struct {
+	Root        string
+	Files       int
+	Size        int64
+	Largest     ContextEntries
+	Directories ContextEntries
+	Findings    []api.ContextFinding
+}
+
+func analyzeBuildContext(ctx *cobra.Command, path string) {
+	root, filename, err := api.ResolveDockerfile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	excludes, err := api.ReadDockerignore(root)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files, err := api.ListBuildContext(root, filename, excludes)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rules, err := config.ContextRules.WithDefaults()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	analysis := ContextAnalysis{
+		Root: root,
+	}
+
+	analysis.Findings, err = api.ScanContext(files, rules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dirs := map[string]*ContextEntry{}
+	for _, file := range files {
+		if file.IsDir {
+			continue
+		}
+
+		analysis.Files++
+		analysis.Size += file.Size
+		analysis.Largest = append(analysis.Largest, ContextEntry{
+			Path:  file.Name,
+			Type:  "file",
+			Files: 1,
+			Size:  file.Size,
+		})
+
+		for dir := filepath.Dir(file.Name); dir != "."; dir = filepath.Dir(dir) {
+			entry, exists := dirs[dir]
+			if !exists {
+				entry = &ContextEntry{
+					Path: dir,
+					Type: "directory",
+				}
+				dirs[dir] = entry
+			}
+			entry.Files++
+			entry.Size += file.Size
+		}
+	}
+
+	for _, entry := range dirs {
+		analysis.Directories = append(analysis.Directories, *entry)
+	}
+
+	sort.Sort(sort.Reverse(analysis.Largest))
+	if len(analysis.Largest) > CONTEXT_TOP_ENTRIES {
+		analysis.Largest = analysis.Largest[:CONTEXT_TOP_ENTRIES]
+	}
+	sort.Sort(sort.Reverse(analysis.Directories))
+	if len(analysis.Directories) > CONTEXT_TOP_ENTRIES {
+		analysis.Directories = analysis.Directories[:CONTEXT_TOP_ENTRIES]
+	}
+
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), analysis); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	ctx.Printf("Build context: %s, %s file(s), %s MB\n",
+		root, FormatInt(int64(analysis.Files)), FormatFloat(float64(analysis.Size)/1000000))
+
+	var items [][]string
+	for _, entries := range []ContextEntries{analysis.Largest, analysis.Directories} {
+		for _, entry := range entries {
+			out := []string{
+				entry.Path,
+				entry.Type,
+				FormatInt(int64(entry.Files)),
+				FormatFloat(float64(entry.Size) / 1000000),
+			}
+			items = append(items, out)
+		}
+	}
+
+	header := []string{
+		"Path",
+		"Type",
+		"Files",
+		"Size(MB)",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+
+	if len(analysis.Findings) == 0 {
+		return
+	}
+
+	items = [][]string{}
+	for _, finding := range analysis.Findings {
+		out := []string{
+			finding.Name,
+			finding.Rule,
+			finding.Message,
+		}
+		items = append(items, out)
+	}
+
+	header = []string{
+		"Path",
+		"Rule",
+		"Message",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+}
@@ -0,0 +1,421 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+const (
+	DF_TOP_CONSUMERS = 10
+)
+
+var (
+	boolAllHosts, boolVolumes bool
+)
+
+type DiskUsageSummary struct {
+	Type   string
+	Count  int
+	Size   int64
+	Shared int64
+	Unique int64
+}
+
+type DiskConsumer struct {
+	Type string
+	ID   string
+	Name string
+	Size int64
+}
+
+type DiskConsumers []DiskConsumer
+
+func (consumers DiskConsumers) Len() int {
+	return len(consumers)
+}
+
+func (consumers DiskConsumers) Swap(i, j int) {
+	consumers[i], consumers[j] = consumers[j], consumers[i]
+}
+
+func (consumers DiskConsumers) Less(i, j int) bool {
+	return consumers[i].Size < consumers[j].Size
+}
+
+type DiskUsage struct {
+	Host      string
+	Summaries []DiskUsageSummary
+	Top       DiskConsumers
+}
+
+var cmdShowDiskUsage = &cobra.Command{
+	Use:   "df [NAME]",
+	Short: "Show the disk usage of the host",
+	Long:  APP_NAME + " host df - Show the disk usage of the host",
+	Run:   showDiskUsage,
+}
+
+func init() {
+	flags := cmdShowDiskUsage.Flags()
+	addMultiHostFlags(flags)
+	flags.BoolVar(&boolVolumes, "volumes", false, "Include the sizes of volumes, which runs a helper container")
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdHost.AddCommand(cmdShowDiskUsage)
+}
+
+func showDiskUsage(ctx *cobra.Command, args []string) {
+	if len(args) > 0 {
+		hostName = args[0]
+	}
+
+	hosts, err := getTargetHosts()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var results []HostResult
+	if hosts == nil {
+		usage, err := getDiskUsage(ctx, hostName)
+		results = append(results, HostResult{hostName, usage, err})
+	} else {
+		results = forEachHost(hosts, func(name string) (interface{}, error) {
+			return getDiskUsage(ctx, name)
+		})
+	}
+
+	var usages []DiskUsage
+	for _, result := range results {
+		if result.Error == nil {
+			usages = append(usages, *result.Value.(*DiskUsage))
+		}
+	}
+
+	// Outside of the timeouts above, so that the helper containers are always removed before exiting
+	if boolVolumes {
+		var wg sync.WaitGroup
+		for i := range usages {
+			wg.Add(1)
+			go func(usage *DiskUsage) {
+				defer wg.Done()
+				addVolumeUsage(ctx, usage)
+			}(&usages[i])
+		}
+		wg.Wait()
+	}
+
+	for i := range usages {
+		usages[i].sortTop()
+	}
+
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), usages); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		for i, usage := range usages {
+			if i > 0 {
+				ctx.Println()
+			}
+			printDiskUsage(ctx, &usage)
+		}
+	}
+
+	if err := reportHostErrors(results); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func getDiskUsage(ctx *cobra.Command, name string) (*DiskUsage, error) {
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := config.GetHost(name)
+	if err != nil {
+		return nil, err
+	}
+
+	docker, err := client.NewDockerClient(configPath, name, ctx.Out())
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := docker.ListImages(true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := docker.ListContainers(true, true, 0, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &DiskUsage{
+		Host: host.Name,
+	}
+
+	summary, top := computeImageUsage(images)
+	usage.Summaries = append(usage.Summaries, summary)
+	usage.Top = append(usage.Top, top...)
+
+	summary = DiskUsageSummary{
+		Type: "Containers",
+	}
+	for _, container := range containers {
+		summary.Count++
+		summary.Size += container.SizeRw
+		name := ""
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		usage.Top = append(usage.Top, DiskConsumer{
+			Type: "container",
+			ID:   container.Id,
+			Name: name,
+			Size: container.SizeRw,
+		})
+	}
+	summary.Unique = summary.Size
+	usage.Summaries = append(usage.Summaries, summary)
+
+	return usage, nil
+}
+
+func addVolumeUsage(ctx *cobra.Command, usage *DiskUsage) {
+	docker, err := client.NewDockerClient(configPath, usage.Host, ctx.Out())
+	if err == nil {
+		var (
+			summary DiskUsageSummary
+			top     []DiskConsumer
+		)
+		summary, top, err = getVolumeUsage(docker)
+		if err == nil {
+			usage.Summaries = append(usage.Summaries, summary)
+			usage.Top = append(usage.Top, top...)
+			return
+		}
+	}
+	log.Warnf("%s: Can't get the sizes of volumes: %s", usage.Host, err)
+}
+
+// Keeps only the largest consumers
+func (usage *DiskUsage) sortTop() {
+	sort.Sort(sort.Reverse(usage.Top))
+	if len(usage.Top) > DF_TOP_CONSUMERS {
+		usage.Top = usage.Top[:DF_TOP_CONSUMERS]
+	}
+}
+
+// Sums up the layers of all images, and the unique size of each top-level image
+func computeImageUsage(images []api.Image) (DiskUsageSummary, []DiskConsumer) {
+	var (
+		summary = DiskUsageSummary{
+			Type: "Images",
+		}
+		byId     = map[string]api.Image{}
+		children = map[string]int{}
+		refs     = map[string]int{}
+		tops     []api.Image
+		top      []DiskConsumer
+	)
+
+	for _, image := range images {
+		byId[image.Id] = image
+		children[image.ParentId]++
+		summary.Size += image.Size
+	}
+
+	for _, image := range images {
+		if (children[image.Id] == 0) || (getImageTag(image.RepoTags) != "") {
+			tops = append(tops, image)
+		}
+	}
+	summary.Count = len(tops)
+
+	chain := func(image api.Image) []api.Image {
+		var layers []api.Image
+		for {
+			layers = append(layers, image)
+			parent, exists := byId[image.ParentId]
+			if !exists {
+				return layers
+			}
+			image = parent
+		}
+	}
+
+	for _, image := range tops {
+		for _, layer := range chain(image) {
+			refs[layer.Id]++
+		}
+	}
+
+	for id, count := range refs {
+		if count > 1 {
+			summary.Shared += byId[id].Size
+		}
+	}
+	summary.Unique = summary.Size - summary.Shared
+
+	for _, image := range tops {
+		var unique int64
+		for _, layer := range chain(image) {
+			if refs[layer.Id] == 1 {
+				unique += layer.Size
+			}
+		}
+		name := getImageTag(image.RepoTags)
+		if name == "" {
+			name = "<none>"
+		}
+		top = append(top, DiskConsumer{
+			Type: "image",
+			ID:   image.Id,
+			Name: name,
+			Size: unique,
+		})
+	}
+
+	return summary, top
+}
+
+func getVolumeUsage(docker *api.DockerClient) (DiskUsageSummary, []DiskConsumer, error) {
+	summary := DiskUsageSummary{
+		Type: "Volumes",
+	}
+
+	volumes, err := getVolumes(docker)
+	if err != nil {
+		return summary, nil, err
+	}
+
+	var (
+		config     api.Config
+		hostConfig api.HostConfig
+		top        []DiskConsumer
+	)
+
+	for _, volume := range volumes {
+		hostConfig.Binds = append(hostConfig.Binds, volume.Path+":/.docker_volumes/"+volume.ID+":ro")
+	}
+	if len(hostConfig.Binds) == 0 {
+		return summary, nil, nil
+	}
+
+	config.Cmd = []string{"/bin/sh", "-c", "du -sk /.docker_volumes/*"}
+	config.Image = "busybox:latest"
+
+	cid, err := docker.CreateContainer("", config, hostConfig)
+	if apiErr, ok := err.(api.Error); ok && (apiErr.StatusCode == 404) {
+		if err := pullImageInSilence(docker, config.Image); err != nil {
+			return summary, nil, err
+		}
+		cid, err = docker.CreateContainer("", config, hostConfig)
+	}
+	if err != nil {
+		return summary, nil, err
+	}
+	defer docker.RemoveContainer(cid, true)
+
+	if err := docker.StartContainer(cid); err != nil {
+		return summary, nil, err
+	}
+
+	if _, err := docker.WaitContainer(cid); err != nil {
+		return summary, nil, err
+	}
+
+	logs, err := docker.GetContainerLogs(cid, false, true, true, false, 0)
+	if err != nil {
+		return summary, nil, err
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(logs[0]), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		size *= 1024
+
+		id := filepath.Base(fields[1])
+		name := id
+		if volume := volumes.FindById(id); (volume != nil) && (len(volume.MountedOn) > 0) {
+			mount := volume.MountedOn[0]
+			name = fmt.Sprintf("%s:%s", mount.ContainerName, mount.MountToPath)
+		}
+
+		summary.Count++
+		summary.Size += size
+		top = append(top, DiskConsumer{
+			Type: "volume",
+			ID:   id,
+			Name: name,
+			Size: size,
+		})
+	}
+	summary.Unique = summary.Size
+
+	return summary, top, nil
+}
+
+func printDiskUsage(ctx *cobra.Command, usage *DiskUsage) {
+	ctx.Printf("Host: %s\n", usage.Host)
+
+	var items [][]string
+	for _, summary := range usage.Summaries {
+		out := []string{
+			summary.Type,
+			FormatInt(int64(summary.Count)),
+			FormatFloat(float64(summary.Size) / 1000000),
+			FormatFloat(float64(summary.Shared) / 1000000),
+			FormatFloat(float64(summary.Unique) / 1000000),
+		}
+		items = append(items, out)
+	}
+
+	header := []string{
+		"Type",
+		"Count",
+		"Size(MB)",
+		"Shared(MB)",
+		"Unique(MB)",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+
+	items = [][]string{}
+	for _, consumer := range usage.Top {
+		out := []string{
+			consumer.Type,
+			Truncate(consumer.ID, 12),
+			FormatNonBreakingString(consumer.Name),
+			FormatFloat(float64(consumer.Size) / 1000000),
+		}
+		items = append(items, out)
+	}
+
+	header = []string{
+		"Type",
+		"ID",
+		"Name",
+		"Size(MB)",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+}
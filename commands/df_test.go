@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+func TestComputeImageUsage(t *testing.T) {
+	images := []api.Image{
+		{Id: "base", Size: 100, RepoTags: []string{"<none>:<none>"}},
+		{Id: "app1", ParentId: "base", Size: 10, RepoTags: []string{"app:1"}},
+		{Id: "app2", ParentId: "base", Size: 20, RepoTags: []string{"app:2"}},
+		{Id: "busybox", Size: 5, RepoTags: []string{"busybox:latest"}},
+	}
+
+	summary, top := computeImageUsage(images)
+
+	expected := DiskUsageSummary{
+		Type:   "Images",
+		Count:  3,
+		Size:   135,
+		Shared: 100,
+		Unique: 35,
+	}
+	if summary != expected {
+		t.Errorf("got %v\nwant %v", summary, expected)
+	}
+
+	sizes := map[string]int64{}
+	for _, consumer := range top {
+		sizes[consumer.Name] = consumer.Size
+	}
+	if (sizes["app:1"] != 10) || (sizes["app:2"] != 20) || (sizes["busybox:latest"] != 5) {
+		t.Errorf("got %v", sizes)
+	}
+}
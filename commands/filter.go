@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+const (
+	SORT_CREATED = "created"
+	SORT_SIZE    = "size"
+	SORT_NAME    = "name"
+	SORT_STATUS  = "status"
+)
+
+var (
+	filterArgs    []string
+	since, before string
+	sortBy        string
+)
+
+// Filters which the daemon handles by itself, and the others filtered here
+var (
+	daemonContainerFilters = []string{"status", "exited"}
+	clientContainerFilters = []string{"name", "label"}
+	daemonImageFilters     = []string{"dangling"}
+	clientImageFilters     = []string{"label"}
+)
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Splits key=value filters into the ones for the daemon and the others
+func parseFilters(args []string, daemonKeys, clientKeys []string) (map[string][]string, map[string][]string, error) {
+	daemonFilters := map[string][]string{}
+	clientFilters := map[string][]string{}
+
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if (len(kv) != 2) || (kv[0] == "") {
+			return nil, nil, fmt.Errorf("Invalid filter: %s, it must be in the form of key=value", arg)
+		}
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		switch {
+		case containsString(daemonKeys, key):
+			daemonFilters[key] = append(daemonFilters[key], kv[1])
+		case containsString(clientKeys, key):
+			clientFilters[key] = append(clientFilters[key], kv[1])
+		default:
+			return nil, nil, fmt.Errorf("Invalid filter: %s, available keys are %s",
+				key, strings.Join(append(append([]string{}, daemonKeys...), clientKeys...), ", "))
+		}
+	}
+
+	return daemonFilters, clientFilters, nil
+}
+
+func validateSortField(field string, fields ...string) error {
+	if (field == "") || containsString(fields, field) {
+		return nil
+	}
+	return fmt.Errorf("Invalid sort field: %s, available fields are %s", field, strings.Join(fields, ", "))
+}
+
+// Matches labels against key or key=value patterns, all of which must match
+func matchLabels(labels map[string]string, patterns []string) bool {
+	for _, pattern := range patterns {
+		kv := strings.SplitN(pattern, "=", 2)
+		value, exists := labels[kv[0]]
+		if !exists {
+			return false
+		}
+		if (len(kv) == 2) && (value != kv[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches any of the names against any of the patterns as a substring
+func matchNames(names []string, patterns []string) bool {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if strings.Contains(strings.TrimPrefix(name, "/"), pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func filterContainers(docker *api.DockerClient, containers []api.Container, filters map[string][]string) ([]api.Container, error) {
+	if len(filters) == 0 {
+		return containers, nil
+	}
+
+	result := []api.Container{}
+	for _, container := range containers {
+		if patterns, exists := filters["name"]; exists && !matchNames(container.Names, patterns) {
+			continue
+		}
+		if patterns, exists := filters["label"]; exists {
+			info, err := docker.InspectContainer(container.Id)
+			if err != nil {
+				return nil, err
+			}
+			if !matchLabels(info.Config.Labels, patterns) {
+				continue
+			}
+		}
+		result = append(result, container)
+	}
+	return result, nil
+}
+
+func filterImages(docker *api.DockerClient, images []api.Image, filters map[string][]string) ([]api.Image, error) {
+	if len(filters) == 0 {
+		return images, nil
+	}
+
+	result := []api.Image{}
+	for _, image := range images {
+		if patterns, exists := filters["label"]; exists {
+			info, err := docker.InspectImage(image.Id)
+			if err != nil {
+				return nil, err
+			}
+			if !matchLabels(info.Config.Labels, patterns) {
+				continue
+			}
+		}
+		result = append(result, image)
+	}
+	return result, nil
+}
+
+// Keeps images created after since and before before, as the daemon doesn't filter them
+func filterImagesByTime(docker *api.DockerClient, images []api.Image, since, before string) ([]api.Image, error) {
+	createdOf := func(name string) (int64, error) {
+		if name == "" {
+			return 0, nil
+		}
+		info, err := docker.InspectImage(name)
+		if err != nil {
+			return 0, err
+		}
+		return info.Created.Unix(), nil
+	}
+
+	after, err := createdOf(since)
+	if err != nil {
+		return nil, err
+	}
+	until, err := createdOf(before)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []api.Image{}
+	for _, image := range images {
+		if (since != "") && (image.Created <= after) {
+			continue
+		}
+		if (before != "") && (image.Created >= until) {
+			continue
+		}
+		result = append(result, image)
+	}
+	return result, nil
+}
+
+type containersByField struct {
+	containers []HostContainer
+	less       func(a, b api.Container) bool
+}
+
+func (s containersByField) Len() int {
+	return len(s.containers)
+}
+
+func (s containersByField) Swap(i, j int) {
+	s.containers[i], s.containers[j] = s.containers[j], s.containers[i]
+}
+
+func (s containersByField) Less(i, j int) bool {
+	a, b := s.containers[i], s.containers[j]
+	if s.less(a.Container, b.Container) {
+		return true
+	}
+	if s.less(b.Container, a.Container) {
+		return false
+	}
+	return a.Host < b.Host
+}
+
+type imagesByField struct {
+	images []HostImage
+	less   func(a, b api.Image) bool
+}
+
+func (s imagesByField) Len() int {
+	return len(s.images)
+}
+
+func (s imagesByField) Swap(i, j int) {
+	s.images[i], s.images[j] = s.images[j], s.images[i]
+}
+
+func (s imagesByField) Less(i, j int) bool {
+	a, b := s.images[i], s.images[j]
+	if s.less(a.Image, b.Image) {
+		return true
+	}
+	if s.less(b.Image, a.Image) {
+		return false
+	}
+	return a.Host < b.Host
+}
+
+func getContainerName(container api.Container) string {
+	if len(container.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(container.Names[0], "/")
+}
+
+// Sorts containers across hosts, the newest or the largest first for created and size,
+// and by host for ties
+func sortContainers(containers []HostContainer, field string) {
+	var less func(a, b api.Container) bool
+	switch field {
+	case SORT_CREATED:
+		less = func(a, b api.Container) bool { return a.Created > b.Created }
+	case SORT_SIZE:
+		less = func(a, b api.Container) bool { return a.SizeRw > b.SizeRw }
+	case SORT_NAME:
+		less = func(a, b api.Container) bool { return getContainerName(a) < getContainerName(b) }
+	case SORT_STATUS:
+		less = func(a, b api.Container) bool { return a.Status < b.Status }
+	default:
+		return
+	}
+	sort.Stable(containersByField{containers, less})
+}
+
+// Sorts images across hosts, the newest or the largest first for created and size,
+// and by host for ties
+func sortImages(images []HostImage, field string) {
+	name := func(image api.Image) string {
+		name := getImageTag(image.RepoTags)
+		if name == "" {
+			// Put untagged images last
+			return "\xff" + image.Id
+		}
+		return name
+	}
+
+	var less func(a, b api.Image) bool
+	switch field {
+	case SORT_CREATED:
+		less = func(a, b api.Image) bool { return a.Created > b.Created }
+	case SORT_SIZE:
+		less = func(a, b api.Image) bool { return a.VirtualSize > b.VirtualSize }
+	case SORT_NAME:
+		less = func(a, b api.Image) bool { return name(a) < name(b) }
+	default:
+		return
+	}
+	sort.Stable(imagesByField{images, less})
+}
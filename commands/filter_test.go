@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+func TestParseFilters(t *testing.T) {
+	daemonFilters, clientFilters, err := parseFilters(
+		[]string{"status=exited", "name=web", "Label=env=prod", "status=paused"},
+		daemonContainerFilters, clientContainerFilters)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expected := map[string][]string{"status": {"exited", "paused"}}
+	if !reflect.DeepEqual(daemonFilters, expected) {
+		t.Errorf("got %v\nwant %v", daemonFilters, expected)
+	}
+	expected = map[string][]string{"name": {"web"}, "label": {"env=prod"}}
+	if !reflect.DeepEqual(clientFilters, expected) {
+		t.Errorf("got %v\nwant %v", clientFilters, expected)
+	}
+
+	for _, arg := range []string{"status", "=exited", "size=1"} {
+		if _, _, err := parseFilters([]string{arg}, daemonContainerFilters, clientContainerFilters); err == nil {
+			t.Errorf("%s: %v", arg, "An invalid filter should be an error.")
+		}
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"env": "prod", "tier": "web"}
+
+	tests := []struct {
+		patterns []string
+		expected bool
+	}{
+		{[]string{"env"}, true},
+		{[]string{"env=prod"}, true},
+		{[]string{"env=prod", "tier=web"}, true},
+		{[]string{"env=dev"}, false},
+		{[]string{"env=prod", "owner"}, false},
+	}
+
+	for _, test := range tests {
+		if matched := matchLabels(labels, test.patterns); matched != test.expected {
+			t.Errorf("%v: got %v\nwant %v", test.patterns, matched, test.expected)
+		}
+	}
+}
+
+func TestSortContainers(t *testing.T) {
+	containers := []HostContainer{
+		{"remote", api.Container{Id: "d", Names: []string{"/web"}, Created: 1, SizeRw: 30, Status: "Up 2 hours"}},
+		{"local", api.Container{Id: "a", Names: []string{"/web"}, Created: 1, SizeRw: 30, Status: "Up 2 hours"}},
+		{"local", api.Container{Id: "b", Names: []string{"/db"}, Created: 3, SizeRw: 10, Status: "Exited (0)"}},
+		{"local", api.Container{Id: "c", Names: []string{"/cache"}, Created: 2, SizeRw: 20, Status: "Up 1 hour"}},
+	}
+
+	tests := []struct {
+		field    string
+		expected []string
+	}{
+		{SORT_CREATED, []string{"b", "c", "a", "d"}},
+		{SORT_SIZE, []string{"a", "d", "c", "b"}},
+		{SORT_NAME, []string{"c", "b", "a", "d"}},
+		{SORT_STATUS, []string{"b", "c", "a", "d"}},
+	}
+
+	for _, test := range tests {
+		sortContainers(containers, test.field)
+		var ids []string
+		for _, container := range containers {
+			ids = append(ids, container.Id)
+		}
+		if !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("%s: got %v\nwant %v", test.field, ids, test.expected)
+		}
+	}
+}
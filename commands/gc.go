@@ -0,0 +1,263 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+var (
+	gcFlags client.GCRules
+)
+
+type GCTag struct {
+	Repository string
+	Tag        string
+	ID         string
+	Created    int64
+	Remove     bool
+	Reason     string
+}
+
+type GCTags []GCTag
+
+func (tags GCTags) Len() int {
+	return len(tags)
+}
+
+func (tags GCTags) Swap(i, j int) {
+	tags[i], tags[j] = tags[j], tags[i]
+}
+
+// By repository, and then the newest first
+func (tags GCTags) Less(i, j int) bool {
+	if tags[i].Repository != tags[j].Repository {
+		return tags[i].Repository < tags[j].Repository
+	}
+	if tags[i].Created != tags[j].Created {
+		return tags[i].Created > tags[j].Created
+	}
+	return tags[i].Tag < tags[j].Tag
+}
+
+var cmdGCImages = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove old image tags by retention rules",
+	Long:  APP_NAME + " image gc - Remove old image tags by retention rules",
+	Run:   gcImages,
+}
+
+func init() {
+	flags := cmdGCImages.Flags()
+	flags.IntVarP(&gcFlags.Keep, "keep", "k", 0, "Number of the newest tags to keep per repository")
+	flags.StringSliceVar(&gcFlags.KeepTags, "keep-tag", nil, "Pattern(s) of tags to keep, e.g. latest,v*")
+	flags.StringVar(&gcFlags.OlderThan, "older-than", "", "Only remove tags older than the given duration, e.g. 720h")
+	flags.BoolVar(&boolDryRun, "dry-run", false, "Only show the plan")
+	flags.BoolVarP(&boolYes, "yes", "y", false, "Remove without confirmation")
+	flags.BoolVarP(&boolAll, "all", "a", false, "Show the tags to keep as well in the plan")
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdImage.AddCommand(cmdGCImages)
+}
+
+func gcImages(ctx *cobra.Command, args []string) {
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rules := client.GCRules{}
+	if config.GC != nil {
+		rules = *config.GC
+	}
+	flags := ctx.Flags()
+	if flags.Lookup("keep").Changed {
+		rules.Keep = gcFlags.Keep
+	}
+	if flags.Lookup("keep-tag").Changed {
+		rules.KeepTags = gcFlags.KeepTags
+	}
+	if flags.Lookup("older-than").Changed {
+		rules.OlderThan = gcFlags.OlderThan
+	}
+
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	images, err := docker.ListImages(false, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	used, err := getUsedImages(docker)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tags, err := planImageGC(images, used, &rules, time.Now())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var removals GCTags
+	for _, tag := range tags {
+		if tag.Remove {
+			removals = append(removals, tag)
+		}
+	}
+
+	plan := removals
+	if boolAll {
+		plan = tags
+	}
+
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), plan); err != nil {
+			log.Fatal(err)
+		}
+		if !boolDryRun && !boolYes && (len(removals) > 0) {
+			log.Warn("No tags removed: needs --yes to remove them with -J, -Y or --format")
+			return
+		}
+	} else {
+		var items [][]string
+		for _, tag := range plan {
+			out := []string{
+				tag.Repository,
+				tag.Tag,
+				Truncate(tag.ID, 12),
+				FormatDateTime(time.Unix(tag.Created, 0)),
+				FormatBool(tag.Remove, "remove", "keep"),
+				tag.Reason,
+			}
+			items = append(items, out)
+		}
+
+		header := []string{
+			"Repository",
+			"Tag",
+			"ID",
+			"Created at",
+			"Action",
+			"Reason",
+		}
+
+		PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+
+		ctx.Printf("%d of %d tag(s) to remove\n", len(removals), len(tags))
+	}
+
+	if boolDryRun || (len(removals) == 0) {
+		return
+	}
+
+	if !boolYes && !confirm(ctx, fmt.Sprintf("Remove %d tag(s)?", len(removals))) {
+		return
+	}
+
+	failed := 0
+	for _, tag := range removals {
+		name := tag.Repository + ":" + tag.Tag
+		if err := docker.RemoveImage(name, false, false); err != nil {
+			log.Errorf("%s: %s", name, err)
+			failed++
+			continue
+		}
+		log.Infof("Removed %s", name)
+	}
+
+	if failed > 0 {
+		log.Fatalf("Failed to remove %d tag(s)", failed)
+	}
+}
+
+func planImageGC(images []api.Image, used map[string]bool, rules *client.GCRules, now time.Time) (GCTags, error) {
+	if (rules.Keep <= 0) && (rules.OlderThan == "") {
+		return nil, errors.New("Needs a retention rule, keep or older-than at least")
+	}
+
+	var threshold time.Time
+	if rules.OlderThan != "" {
+		duration, err := time.ParseDuration(rules.OlderThan)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid duration: %s", rules.OlderThan)
+		}
+		threshold = now.Add(-duration)
+	}
+
+	for _, pattern := range rules.KeepTags {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("Invalid pattern: %s, %s", pattern, err)
+		}
+	}
+
+	var tags GCTags
+	for _, image := range images {
+		for _, repoTag := range image.RepoTags {
+			if repoTag == "<none>:<none>" {
+				continue
+			}
+			n := strings.LastIndex(repoTag, ":")
+			if n < 0 {
+				continue
+			}
+			tags = append(tags, GCTag{
+				Repository: repoTag[:n],
+				Tag:        repoTag[n+1:],
+				ID:         image.Id,
+				Created:    image.Created,
+			})
+		}
+	}
+
+	sort.Sort(tags)
+
+	rank := 0
+	for i := range tags {
+		tag := &tags[i]
+		if (i == 0) || (tags[i-1].Repository != tag.Repository) {
+			rank = 0
+		}
+		rank++
+
+		switch {
+		case used[tag.ID]:
+			tag.Reason = "used by a container"
+		case matchTagPatterns(rules.KeepTags, tag.Tag):
+			tag.Reason = "matches keep-tags"
+		case (rules.Keep > 0) && (rank <= rules.Keep):
+			tag.Reason = fmt.Sprintf("one of the newest %d", rules.Keep)
+		case !threshold.IsZero() && !time.Unix(tag.Created, 0).Before(threshold):
+			tag.Reason = fmt.Sprintf("newer than %s", rules.OlderThan)
+		default:
+			tag.Remove = true
+			if threshold.IsZero() {
+				tag.Reason = fmt.Sprintf("not one of the newest %d", rules.Keep)
+			} else {
+				tag.Reason = fmt.Sprintf("older than %s", rules.OlderThan)
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+func matchTagPatterns(patterns []string, tag string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, tag); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+func TestPlanImageGC(t *testing.T) {
+	var (
+		now = time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+		day = int64(24 * 60 * 60)
+	)
+
+	images := []api.Image{
+		{Id: "a1", Created: now.Unix() - 1*day, RepoTags: []string{"app:v3", "app:latest"}},
+		{Id: "a2", Created: now.Unix() - 10*day, RepoTags: []string{"app:build-2"}},
+		{Id: "a3", Created: now.Unix() - 20*day, RepoTags: []string{"app:build-1"}},
+		{Id: "a4", Created: now.Unix() - 30*day, RepoTags: []string{"app:v1"}},
+		{Id: "b1", Created: now.Unix() - 40*day, RepoTags: []string{"localhost:5000/db:old"}},
+		{Id: "c1", Created: now.Unix() - 50*day, RepoTags: []string{"<none>:<none>"}},
+	}
+	used := map[string]bool{"b1": true}
+
+	tags, err := planImageGC(images, used, &client.GCRules{
+		Keep:      2,
+		KeepTags:  []string{"v*"},
+		OlderThan: "360h",
+	}, now)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var (
+		actual   = map[string]bool{}
+		expected = map[string]bool{
+			"app:latest":            false,
+			"app:v3":                false,
+			"app:build-2":           false,
+			"app:build-1":           true,
+			"app:v1":                false,
+			"localhost:5000/db:old": false,
+		}
+	)
+	for _, tag := range tags {
+		actual[tag.Repository+":"+tag.Tag] = tag.Remove
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v\nwant %v", actual, expected)
+	}
+
+	if _, err := planImageGC(images, used, &client.GCRules{}, now); err == nil {
+		t.Errorf("%v", "GC without any retention rule should be an error.")
+	}
+}
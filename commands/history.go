@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+var (
+	reNopInstruction = regexp.MustCompile("^/bin/sh -c #\\(nop\\) +")
+	reShellCommand   = regexp.MustCompile("^/bin/sh -c +")
+	reAddSource      = regexp.MustCompile("^(ADD|COPY) +(\\w+):([0-9a-f]+) in +(.*)$")
+	reExposedPort    = regexp.MustCompile("([0-9]+/[a-z]+):\\{\\}")
+)
+
+func formatCreatedBy(createdBy string) string {
+	re := regexp.MustCompile("\\s+")
+	createdBy = re.ReplaceAllLiteralString(createdBy, " ")
+	re = regexp.MustCompile("^/bin/sh -c #\\(nop\\) ")
+	createdBy = re.ReplaceAllLiteralString(createdBy, "")
+	re = regexp.MustCompile("^/bin/sh -c")
+	return re.ReplaceAllLiteralString(createdBy, "RUN")
+}
+
+// Returns the nearest ancestor with a tag, and the history on top of it from the oldest
+func splitImageHistory(history api.ImageHistories) (string, api.ImageHistories) {
+	base := ""
+	layers := history
+	for i := 1; i < len(history); i++ {
+		if tag := getImageTag(history[i].Tags); tag != "" {
+			base = tag
+			layers = history[:i]
+			break
+		}
+	}
+
+	var reversed api.ImageHistories
+	for i := len(layers) - 1; i >= 0; i-- {
+		reversed = append(reversed, layers[i])
+	}
+	return base, reversed
+}
+
+func getImageTag(tags []string) string {
+	for _, tag := range tags {
+		if tag != "<none>:<none>" {
+			return tag
+		}
+	}
+	return ""
+}
+
+func getDockerfileInstruction(createdBy string) string {
+	createdBy = strings.TrimSpace(createdBy)
+	if createdBy == "" {
+		return ""
+	}
+
+	if !reNopInstruction.MatchString(createdBy) {
+		if reShellCommand.MatchString(createdBy) {
+			return "RUN " + reShellCommand.ReplaceAllLiteralString(createdBy, "")
+		}
+		return "# " + createdBy
+	}
+
+	instruction := reNopInstruction.ReplaceAllLiteralString(createdBy, "")
+	command := strings.ToUpper(strings.SplitN(instruction, " ", 2)[0])
+
+	switch command {
+	case "CMD", "ENTRYPOINT":
+		// Written from the image config at the end
+		return ""
+	case "ADD", "COPY":
+		if m := reAddSource.FindStringSubmatch(instruction); m != nil {
+			return fmt.Sprintf("%s <%s:%s> %s", m[1], m[2], Truncate(m[3], 12), m[4])
+		}
+	case "EXPOSE":
+		if m := reExposedPort.FindAllStringSubmatch(instruction, -1); m != nil {
+			var ports []string
+			for _, port := range m {
+				ports = append(ports, port[1])
+			}
+			sort.Strings(ports)
+			return "EXPOSE " + strings.Join(ports, " ")
+		}
+	case "VOLUME":
+		value := strings.TrimSpace(strings.TrimPrefix(instruction, "VOLUME"))
+		if strings.HasPrefix(value, "[") && !strings.HasPrefix(value, "[\"") {
+			return "VOLUME " + strings.Trim(value, "[]")
+		}
+	}
+
+	return instruction
+}
+
+func reconstructDockerfile(base string, layers api.ImageHistories, config, baseConfig *api.Config) string {
+	var buf bytes.Buffer
+
+	if base == "" {
+		base = "scratch"
+	}
+	fmt.Fprintf(&buf, "FROM %s\n", base)
+
+	for _, layer := range layers {
+		if instruction := getDockerfileInstruction(layer.CreatedBy); instruction != "" {
+			fmt.Fprintf(&buf, "%s\n", instruction)
+		}
+	}
+
+	if config == nil {
+		return buf.String()
+	}
+	if baseConfig == nil {
+		baseConfig = &api.Config{}
+	}
+
+	for _, field := range []struct {
+		name        string
+		value, base []string
+	}{
+		{"ENTRYPOINT", config.Entrypoint, baseConfig.Entrypoint},
+		{"CMD", config.Cmd, baseConfig.Cmd},
+	} {
+		if (len(field.value) == 0) || reflect.DeepEqual(field.value, field.base) {
+			continue
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s %s\n", field.name, value)
+	}
+
+	return buf.String()
+}
+
+func getImageDockerfile(docker *api.DockerClient, name string, history api.ImageHistories) (string, error) {
+	base, layers := splitImageHistory(history)
+
+	image, err := docker.InspectImage(name)
+	if err != nil {
+		return "", err
+	}
+
+	var baseConfig *api.Config
+	if base != "" {
+		baseImage, err := docker.InspectImage(base)
+		if err != nil {
+			return "", err
+		}
+		baseConfig = &baseImage.Config
+	}
+
+	return reconstructDockerfile(base, layers, &image.Config, baseConfig), nil
+}
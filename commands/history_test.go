@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+func TestReconstructDockerfile(t *testing.T) {
+	// The newest first, as returned by the API
+	history := api.ImageHistories{
+		{Id: "f6", CreatedBy: "/bin/sh -c #(nop) CMD [/app/run]"},
+		{Id: "f5", CreatedBy: "/bin/sh -c #(nop) EXPOSE map[8080/tcp:{} 80/tcp:{}]"},
+		{Id: "f4", CreatedBy: "/bin/sh -c #(nop) VOLUME [/data]"},
+		{Id: "f3", CreatedBy: "/bin/sh -c #(nop) ADD dir:0123456789abcdef0123 in /app"},
+		{Id: "f2", CreatedBy: "/bin/sh -c apt-get update && apt-get install -y curl"},
+		{Id: "f1", CreatedBy: "/bin/sh -c #(nop) ENV LANG=C.UTF-8"},
+		{Id: "e1", CreatedBy: "/bin/sh -c #(nop) CMD [/bin/bash]", Tags: []string{"debian:wheezy"}},
+		{Id: "e0", CreatedBy: "/bin/sh -c #(nop) ADD file:aaaa in /"},
+	}
+
+	base, layers := splitImageHistory(history)
+	if base != "debian:wheezy" {
+		t.Errorf("got %v\nwant %v", base, "debian:wheezy")
+	}
+
+	var (
+		actual = reconstructDockerfile(base, layers,
+			&api.Config{Cmd: []string{"/app/run"}, Entrypoint: []string{"/bin/sh", "-c"}},
+			&api.Config{Cmd: []string{"/bin/bash"}, Entrypoint: []string{"/bin/sh", "-c"}})
+		expected = `FROM debian:wheezy
+ENV LANG=C.UTF-8
+RUN apt-get update && apt-get install -y curl
+ADD <dir:0123456789ab> /app
+VOLUME /data
+EXPOSE 80/tcp 8080/tcp
+CMD ["/app/run"]
+`
+	)
+	if actual != expected {
+		t.Errorf("got %v\nwant %v", actual, expected)
+	}
+}
@@ -5,18 +5,21 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/yungsang/tablewriter"
 
+	"github.com/ailispaw/talk2docker/api"
 	"github.com/ailispaw/talk2docker/client"
 )
 
 var (
 	boolTLS, boolTLSVerify                 bool
 	pathTLSCaCert, pathTLSCert, pathTLSKey string
+	hostTLSMinVersion                      string
 )
 
 var cmdHosts = &cobra.Command{
@@ -85,6 +88,8 @@ func init() {
 	for _, flags := range []*pflag.FlagSet{cmdHosts.Flags(), cmdListHosts.Flags()} {
 		flags.BoolVarP(&boolQuiet, "quiet", "q", false, "Only display host names")
 		flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+		flags.StringSliceVar(&columnNames, "columns", []string{}, "Columns to show: default, name, url, description, tls, status, latency, version, api-version, cert-expiry")
+		flags.BoolVar(&boolStatus, "status", false, "Ping the hosts in parallel and show their status")
 	}
 
 	cmdHost.AddCommand(cmdListHosts)
@@ -93,14 +98,12 @@ func init() {
 
 	flags := cmdGetHostInfo.Flags()
 	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	addMultiHostFlags(flags)
 	cmdHost.AddCommand(cmdGetHostInfo)
 
 	flags = cmdAddHost.Flags()
-	flags.BoolVar(&boolTLS, "tls", false, "Use TLS; implied by --tls-verify flag")
-	flags.StringVar(&pathTLSCaCert, "tls-ca-cert", "", "Path to a certificate signed by the CA")
-	flags.StringVar(&pathTLSCert, "tls-cert", "", "Path to TLS certificate file")
-	flags.StringVar(&pathTLSKey, "tls-key", "", "Path to TLS key file")
-	flags.BoolVar(&boolTLSVerify, "tls-verify", false, "Use TLS and verify the remote")
+	addTLSFlags(flags)
+	addHostSettingFlags(flags)
 	cmdHost.AddCommand(cmdAddHost)
 
 	cmdHost.AddCommand(cmdRemoveHost)
@@ -108,6 +111,15 @@ func init() {
 	cmdHost.AddCommand(cmdExportHostEnv)
 }
 
+func addTLSFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&boolTLS, "tls", false, "Use TLS; implied by --tls-verify flag")
+	flags.StringVar(&pathTLSCaCert, "tls-ca-cert", "", "Path to a certificate signed by the CA; the system roots without it")
+	flags.StringVar(&pathTLSCert, "tls-cert", "", "Path to TLS client certificate file, optional")
+	flags.StringVar(&pathTLSKey, "tls-key", "", "Path to TLS client key file, optional")
+	flags.BoolVar(&boolTLSVerify, "tls-verify", false, "Use TLS and verify the remote")
+	flags.StringVar(&hostTLSMinVersion, "tls-min-version", "", "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3 (default "+client.DEFAULT_TLS_MIN_VERSION+")")
+}
+
 func listHosts(ctx *cobra.Command, args []string) {
 	config, err := client.LoadConfig(configPath)
 	if err != nil {
@@ -121,34 +133,87 @@ func listHosts(ctx *cobra.Command, args []string) {
 		return
 	}
 
-	if boolYAML || boolJSON {
-		if err := FormatPrint(ctx.Out(), config.Hosts); err != nil {
-			log.Fatal(err)
-		}
-		return
+	hosts := config.Hosts
+
+	var statuses map[string]*HostStatus
+	getStatus := func(i int) *HostStatus {
+		return statuses[hosts[i].Name]
+	}
+
+	columns, err := selectColumns([]Column{
+		{"default", "", false, func(i int) string {
+			return FormatBool(hosts[i].Name == config.Default, "*", "")
+		}},
+		{"name", "Name", false, func(i int) string {
+			return hosts[i].Name
+		}},
+		{"url", "URL", false, func(i int) string {
+			return hosts[i].URL
+		}},
+		{"description", "Description", false, func(i int) string {
+			return FormatNonBreakingString(hosts[i].Description)
+		}},
+		{"tls", "TLS", false, func(i int) string {
+			return FormatBool(hosts[i].TLS, "YES", "")
+		}},
+		{"status", "Status", !boolStatus, func(i int) string {
+			if status := getStatus(i); status.Reachable {
+				return "Up"
+			}
+			return "Down"
+		}},
+		{"latency", "Latency(ms)", !boolStatus, func(i int) string {
+			if status := getStatus(i); status.Reachable {
+				return FormatFloat(float64(status.Latency) / float64(time.Millisecond))
+			}
+			return ""
+		}},
+		{"version", "Version", !boolStatus, func(i int) string {
+			return getStatus(i).Version
+		}},
+		{"api-version", "API Version", !boolStatus, func(i int) string {
+			return getStatus(i).ApiVersion
+		}},
+		{"cert-expiry", "Cert Expiry", !boolStatus, func(i int) string {
+			return formatCertExpiry(getStatus(i).CertExpiry)
+		}},
+	}, columnNames, false)
+	if err != nil {
+		ErrorExit(ctx, err.Error())
 	}
 
-	var items [][]string
-	for _, host := range config.Hosts {
-		out := []string{
-			FormatBool(host.Name == config.Default, "*", ""),
-			host.Name,
-			host.URL,
-			FormatNonBreakingString(host.Description),
-			FormatBool(host.TLS, "YES", ""),
+	if boolStatus || hasColumn(columns, "status") || hasColumn(columns, "latency") ||
+		hasColumn(columns, "version") || hasColumn(columns, "api-version") || hasColumn(columns, "cert-expiry") {
+		names := []string{}
+		for _, host := range hosts {
+			names = append(names, host.Name)
+		}
+		statuses = getHostStatuses(ctx, names)
+		for _, host := range hosts {
+			if status := statuses[host.Name]; !status.Reachable {
+				log.Warnf("%s: %s", host.Name, status.Error)
+			}
 		}
-		items = append(items, out)
 	}
 
-	header := []string{
-		"",
-		"Name",
-		"URL",
-		"Description",
-		"TLS",
+	if boolYAML || boolJSON || (format != "") {
+		if statuses != nil {
+			items := []*HostStatus{}
+			for _, host := range hosts {
+				items = append(items, statuses[host.Name])
+			}
+			if err := FormatPrint(ctx.Out(), items); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		if err := FormatPrint(ctx.Out(), hosts); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+	PrintColumns(ctx.Out(), columns, len(hosts))
 }
 
 func switchHost(ctx *cobra.Command, args []string) {
@@ -158,6 +223,8 @@ func switchHost(ctx *cobra.Command, args []string) {
 
 	name := args[0]
 
+	defer lockConfig().Unlock()
+
 	config, err := client.LoadConfig(configPath)
 	if err != nil {
 		log.Fatal(err)
@@ -177,41 +244,145 @@ func switchHost(ctx *cobra.Command, args []string) {
 	listHosts(ctx, args)
 }
 
+type HostInfo struct {
+	Host        *client.Host
+	Info        *api.Info
+	Certificate *CertificateInfo `json:",omitempty" yaml:",omitempty"`
+}
+
+// The server certificate of a TLS host
+type CertificateInfo struct {
+	Subject  string
+	SANs     []string
+	NotAfter time.Time
+	DaysLeft int
+}
+
+// Returns the server certificate of the host, or nil without TLS
+func getCertificateInfo(docker *api.DockerClient) (*CertificateInfo, error) {
+	ping, err := docker.Ping()
+	if err != nil {
+		return nil, err
+	}
+	cert := ping.Certificate
+	if cert == nil {
+		return nil, nil
+	}
+
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	return &CertificateInfo{
+		Subject:  cert.Subject.String(),
+		SANs:     sans,
+		NotAfter: cert.NotAfter,
+		DaysLeft: int(cert.NotAfter.Sub(time.Now()).Hours() / 24),
+	}, nil
+}
+
 func getHostInfo(ctx *cobra.Command, args []string) {
 	if len(args) > 0 {
 		hostName = args[0]
 	}
 
-	config, err := client.LoadConfig(configPath)
+	hosts, err := getTargetHosts()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	host, err := config.GetHost(hostName)
-	if err != nil {
-		log.Fatal(err)
+	getInfo := func(name string) (interface{}, error) {
+		config, err := client.LoadConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+
+		host, err := config.GetHost(name)
+		if err != nil {
+			return nil, err
+		}
+
+		docker, err := client.NewDockerClient(configPath, name, ctx.Out())
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := docker.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		hostInfo := &HostInfo{Host: host, Info: info}
+
+		if host.TLS {
+			if hostInfo.Certificate, err = getCertificateInfo(docker); err != nil {
+				return nil, err
+			}
+		}
+
+		return hostInfo, nil
 	}
 
-	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
-	if err != nil {
-		log.Fatal(err)
+	if hosts == nil {
+		value, err := getInfo(hostName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		hostInfo := value.(*HostInfo)
+
+		if format != "" {
+			if err := PrintInTemplate(ctx.Out(), format, hostInfo); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+
+		if boolYAML || boolJSON {
+			data := []interface{}{hostInfo.Host, hostInfo.Info}
+			if hostInfo.Certificate != nil {
+				data = append(data, hostInfo.Certificate)
+			}
+			if err := FormatPrint(ctx.Out(), data); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+
+		printHostInfo(ctx, hostInfo)
+		return
 	}
 
-	info, err := docker.Info()
-	if err != nil {
-		log.Fatal(err)
+	results := forEachHost(hosts, getInfo)
+
+	hostInfos := []*HostInfo{}
+	for _, result := range results {
+		if result.Error == nil {
+			hostInfos = append(hostInfos, result.Value.(*HostInfo))
+		}
 	}
 
-	if boolYAML || boolJSON {
-		data := make([]interface{}, 2)
-		data[0] = host
-		data[1] = info
-		if err := FormatPrint(ctx.Out(), data); err != nil {
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), hostInfos); err != nil {
 			log.Fatal(err)
 		}
-		return
+	} else {
+		for i, hostInfo := range hostInfos {
+			if i > 0 {
+				ctx.Println()
+			}
+			printHostInfo(ctx, hostInfo)
+		}
 	}
 
+	if err := reportHostErrors(results); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func printHostInfo(ctx *cobra.Command, hostInfo *HostInfo) {
+	host, info := hostInfo.Host, hostInfo.Info
+
 	var items [][]string
 
 	items = append(items, []string{
@@ -228,19 +399,49 @@ func getHostInfo(ctx *cobra.Command, args []string) {
 	})
 	if host.TLS {
 		items = append(items, []string{
-			FormatNonBreakingString("  CA Certificate file"), FormatNonBreakingString(host.TLSCaCert),
+			FormatNonBreakingString("  CA Certificate file"), FormatNonBreakingString(FormatBool(host.TLSCaCert != "", host.TLSCaCert, "System roots")),
 		})
 		items = append(items, []string{
-			FormatNonBreakingString("  Certificate file"), FormatNonBreakingString(host.TLSCert),
+			FormatNonBreakingString("  Certificate file"), FormatNonBreakingString(FormatBool(host.TLSCert != "", host.TLSCert, "None")),
 		})
 		items = append(items, []string{
-			FormatNonBreakingString("  Key file"), FormatNonBreakingString(host.TLSKey),
+			FormatNonBreakingString("  Key file"), FormatNonBreakingString(FormatBool(host.TLSKey != "", host.TLSKey, "None")),
 		})
 		items = append(items, []string{
 			FormatNonBreakingString("  Verify"), FormatBool(host.TLSVerify, "Required", "No"),
 		})
+		items = append(items, []string{
+			FormatNonBreakingString("  Minimum Version"), FormatBool(host.TLSMinVersion != "", host.TLSMinVersion, client.DEFAULT_TLS_MIN_VERSION),
+		})
+		if host.TLSServerName != "" {
+			items = append(items, []string{
+				FormatNonBreakingString("  Server Name"), host.TLSServerName,
+			})
+		}
+		if cert := hostInfo.Certificate; cert != nil {
+			items = append(items, []string{
+				FormatNonBreakingString("  Server Certificate"), FormatNonBreakingString(cert.Subject),
+			})
+			items = append(items, []string{
+				FormatNonBreakingString("    SANs"), FormatNonBreakingString(strings.Join(cert.SANs, ", ")),
+			})
+			items = append(items, []string{
+				FormatNonBreakingString("    Expires"), FormatNonBreakingString(fmt.Sprintf("%s (%s)", FormatDateTime(cert.NotAfter), formatDaysLeft(cert.DaysLeft))),
+			})
+		}
 	}
 
+	timeout, _ := host.GetTimeout()
+	items = append(items, []string{
+		"Timeout", timeout.String(),
+	})
+	items = append(items, []string{
+		"API Version", FormatBool(host.ApiVersion != "", host.ApiVersion, api.API_VERSION),
+	})
+	items = append(items, []string{
+		"Default Registry", FormatBool(host.DefaultRegistry != "", host.DefaultRegistry, "Docker Hub"),
+	})
+
 	items = append(items, []string{
 		"Containers", strconv.Itoa(info.Containers),
 	})
@@ -323,6 +524,16 @@ func getHostInfo(ctx *cobra.Command, args []string) {
 	PrintInTable(ctx.Out(), nil, items, 0, tablewriter.ALIGN_LEFT)
 }
 
+func formatDaysLeft(days int) string {
+	switch {
+	case days < 0:
+		return "expired"
+	case days == 1:
+		return "1 day left"
+	}
+	return fmt.Sprintf("%d days left", days)
+}
+
 func addHost(ctx *cobra.Command, args []string) {
 	if len(args) < 2 {
 		ErrorExit(ctx, "Needs two arguments <NAME> and <URL> at least")
@@ -338,6 +549,8 @@ func addHost(ctx *cobra.Command, args []string) {
 		desc = strings.Join(args[2:], " ")
 	}
 
+	defer lockConfig().Unlock()
+
 	config, err := client.LoadConfig(configPath)
 	if err != nil {
 		log.Fatal(err)
@@ -371,6 +584,10 @@ func addHost(ctx *cobra.Command, args []string) {
 		newHost.TLSVerify = boolTLSVerify
 	}
 
+	if err := applyHostSettingFlags(ctx, &newHost); err != nil {
+		log.Fatal(err)
+	}
+
 	config.Default = newHost.Name
 	config.Hosts = append(config.Hosts, newHost)
 
@@ -388,6 +605,8 @@ func removeHost(ctx *cobra.Command, args []string) {
 
 	name := args[0]
 
+	defer lockConfig().Unlock()
+
 	config, err := client.LoadConfig(configPath)
 	if err != nil {
 		log.Fatal(err)
@@ -435,7 +654,13 @@ func exportHostEnv(ctx *cobra.Command, args []string) {
 
 	ctx.Printf("export DOCKER_HOST=%s;\n", host.URL)
 	if host.TLS {
-		ctx.Printf("export DOCKER_CERT_PATH=%s;\n", filepath.Dir(host.TLSCert))
+		// Docker cli expects cert.pem and key.pem in DOCKER_CERT_PATH
+		if host.TLSCert != "" {
+			ctx.Printf("export DOCKER_CERT_PATH=%s;\n", filepath.Dir(host.TLSCert))
+		} else {
+			log.Warnf("%s has no client certificate, so DOCKER_CERT_PATH is not set", host.Name)
+			ctx.Printf("unset DOCKER_CERT_PATH;\n")
+		}
 		ctx.Printf("export DOCKER_TLS_VERIFY=%s;\n", FormatBool(host.TLSVerify, "true", "false"))
 	} else {
 		ctx.Printf("unset DOCKER_CERT_PATH;\n")
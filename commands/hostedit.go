@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+var (
+	hostURL, hostDescription string
+	boolNoVerify             bool
+
+	hostTimeout, hostApiVersion, hostDefaultRegistry, hostTLSServerName string
+)
+
+var cmdUpdateHost = &cobra.Command{
+	Use:   "update <NAME>",
+	Short: "Update a host in the configuration file",
+	Long:  APP_NAME + " host update - Update a host in the configuration file",
+	Run:   updateHost,
+}
+
+var cmdRenameHost = &cobra.Command{
+	Use:     "rename <OLD-NAME> <NEW-NAME>",
+	Aliases: []string{"mv"},
+	Short:   "Rename a host in the configuration file",
+	Long:    APP_NAME + " host rename - Rename a host in the configuration file",
+	Run:     renameHost,
+}
+
+var cmdCopyHost = &cobra.Command{
+	Use:     "copy <NAME> <NEW-NAME>",
+	Aliases: []string{"cp"},
+	Short:   "Copy a host into a new one in the configuration file",
+	Long:    APP_NAME + " host copy - Copy a host into a new one in the configuration file",
+	Run:     copyHost,
+}
+
+func init() {
+	for _, flags := range []*pflag.FlagSet{cmdUpdateHost.Flags(), cmdCopyHost.Flags()} {
+		flags.StringVar(&hostURL, "url", "", "URL of the Docker daemon")
+		flags.StringVar(&hostDescription, "description", "", "Description of the host")
+		addTLSFlags(flags)
+		addHostSettingFlags(flags)
+		flags.BoolVar(&boolNoVerify, "no-verify", false, "Save without connecting to the host")
+	}
+
+	cmdHost.AddCommand(cmdUpdateHost)
+	cmdHost.AddCommand(cmdRenameHost)
+	cmdHost.AddCommand(cmdCopyHost)
+}
+
+func addHostSettingFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&hostTLSServerName, "tls-server-name", "", "Server name to verify the certificate with, instead of the host in the URL")
+	flags.StringVar(&hostTimeout, "timeout", "", "Timeout to connect to the host, e.g. 10s (default 30s)")
+	flags.StringVar(&hostApiVersion, "api-version", "", "Docker Remote API version to use, e.g. 1.16 (default "+api.API_VERSION+")")
+	flags.StringVar(&hostDefaultRegistry, "default-registry", "", "Registry for image names without one, instead of the Docker Hub")
+}
+
+// Applies the setting flags changed on the command line to the host, and validates them
+func applyHostSettingFlags(ctx *cobra.Command, host *client.Host) error {
+	flags := ctx.Flags()
+
+	if flags.Changed("tls-server-name") {
+		host.TLSServerName = hostTLSServerName
+	}
+	if flags.Changed("tls-min-version") {
+		host.TLSMinVersion = hostTLSMinVersion
+	}
+	if flags.Changed("timeout") {
+		host.Timeout = hostTimeout
+	}
+	if flags.Changed("api-version") {
+		host.ApiVersion = hostApiVersion
+	}
+	if flags.Changed("default-registry") {
+		host.DefaultRegistry = hostDefaultRegistry
+	}
+
+	return host.ValidateSettings()
+}
+
+// Applies the flags changed on the command line to the host
+func applyHostFlags(ctx *cobra.Command, host *client.Host) error {
+	flags := ctx.Flags()
+
+	if flags.Changed("url") {
+		host.URL = hostURL
+	}
+	if flags.Changed("description") {
+		host.Description = hostDescription
+	}
+	if flags.Changed("tls") {
+		host.TLS = boolTLS
+	}
+	if flags.Changed("tls-ca-cert") {
+		host.TLSCaCert = pathTLSCaCert
+	}
+	if flags.Changed("tls-cert") {
+		host.TLSCert = pathTLSCert
+	}
+	if flags.Changed("tls-key") {
+		host.TLSKey = pathTLSKey
+	}
+	if flags.Changed("tls-verify") {
+		host.TLSVerify = boolTLSVerify
+		if boolTLSVerify {
+			host.TLS = true
+		}
+	}
+
+	return applyHostSettingFlags(ctx, host)
+}
+
+// Connects to the host to make sure of its settings, unless --no-verify
+func verifyHost(ctx *cobra.Command, host *client.Host) error {
+	if boolNoVerify {
+		return nil
+	}
+
+	docker, err := client.NewDockerClientForHost(host, ctx.Out())
+	if err != nil {
+		return err
+	}
+
+	result := forEachHostWithin([]string{host.Name}, PING_TIMEOUT, func(name string) (interface{}, error) {
+		return docker.Ping()
+	})[0]
+	if result.Error != nil {
+		return fmt.Errorf("Can't connect to %s: %s\nUse --no-verify to save it anyway.", host.URL, result.Error)
+	}
+	return nil
+}
+
+func updateHost(ctx *cobra.Command, args []string) {
+	if len(args) < 1 {
+		ErrorExit(ctx, "Needs an argument <NAME> to update")
+	}
+
+	defer lockConfig().Unlock()
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	index := -1
+	for i, host := range config.Hosts {
+		if host.Name == args[0] {
+			index = i
+		}
+	}
+	if index < 0 {
+		log.Fatalf("\"%s\" not found in the config", args[0])
+	}
+
+	host := config.Hosts[index]
+	if err := applyHostFlags(ctx, &host); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := verifyHost(ctx, &host); err != nil {
+		log.Fatal(err)
+	}
+
+	config.Hosts[index] = host
+
+	if err := config.SaveConfig(configPath); err != nil {
+		log.Fatal(err)
+	}
+
+	listHosts(ctx, args)
+}
+
+func renameHost(ctx *cobra.Command, args []string) {
+	if len(args) < 2 {
+		ErrorExit(ctx, "Needs two arguments <OLD-NAME> and <NEW-NAME> to rename")
+	}
+
+	var (
+		oldName = args[0]
+		newName = args[1]
+	)
+
+	defer lockConfig().Unlock()
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := config.GetHost(newName); err == nil {
+		log.Fatalf("\"%s\" already exists", newName)
+	}
+
+	found := false
+	for i, host := range config.Hosts {
+		if host.Name == oldName {
+			config.Hosts[i].Name = newName
+			found = true
+		}
+	}
+	if !found {
+		log.Fatalf("\"%s\" not found in the config", oldName)
+	}
+
+	if config.Default == oldName {
+		config.Default = newName
+	}
+
+	if err := config.SaveConfig(configPath); err != nil {
+		log.Fatal(err)
+	}
+
+	listHosts(ctx, args)
+}
+
+func copyHost(ctx *cobra.Command, args []string) {
+	if len(args) < 2 {
+		ErrorExit(ctx, "Needs two arguments <NAME> and <NEW-NAME> to copy")
+	}
+
+	defer lockConfig().Unlock()
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	host, err := config.GetHost(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := config.GetHost(args[1]); err == nil {
+		log.Fatalf("\"%s\" already exists", args[1])
+	}
+
+	newHost := *host
+	newHost.Name = args[1]
+	if err := applyHostFlags(ctx, &newHost); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := verifyHost(ctx, &newHost); err != nil {
+		log.Fatal(err)
+	}
+
+	config.Hosts = append(config.Hosts, newHost)
+
+	if err := config.SaveConfig(configPath); err != nil {
+		log.Fatal(err)
+	}
+
+	listHosts(ctx, args)
+}
@@ -3,7 +3,7 @@ package commands
 import (
 	"fmt"
 	"os"
-	"regexp"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -20,7 +20,9 @@ import (
 var (
 	imageTag string
 
-	boolForce, boolNoPrune, boolStar bool
+	parallelPulls int
+
+	boolForce, boolNoPrune, boolStar, boolDockerfile bool
 )
 
 var cmdIs = &cobra.Command{
@@ -64,9 +66,9 @@ var cmdBuildImage = &cobra.Command{
 }
 
 var cmdPullImage = &cobra.Command{
-	Use:   "pull <NAME[:TAG]>",
-	Short: "Pull an image from a registry",
-	Long:  APP_NAME + " image pull - Pull an image from a registry",
+	Use:   "pull <NAME[:TAG]>...",
+	Short: "Pull images from a registry",
+	Long:  APP_NAME + " image pull - Pull images from a registry",
 	Run:   pullImage,
 }
 
@@ -120,6 +122,13 @@ func init() {
 		flags.BoolVarP(&boolAll, "all", "a", false, "Show all images. Only named/taged and leaf images are shown by default.")
 		flags.BoolVarP(&boolQuiet, "quiet", "q", false, "Only display numeric IDs")
 		flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+		flags.StringSliceVar(&filterArgs, "filter", []string{}, "Filter output based on key=value (dangling, label)")
+		flags.StringVar(&since, "since", "", "Show only images created since <NAME|ID>")
+		flags.StringVar(&before, "before", "", "Show only images created before <NAME|ID>")
+		flags.StringVar(&sortBy, "sort", "", "Sort by created, size or name")
+		flags.StringSliceVar(&columnNames, "columns", []string{}, "Columns to show: id, name, size, created, parent, labels")
+		flags.BoolVarP(&boolWide, "wide", "w", false, "Show extra columns: parent and labels")
+		addMultiHostFlags(flags)
 	}
 
 	cmdImage.AddCommand(cmdListImages)
@@ -127,12 +136,16 @@ func init() {
 	for _, flags := range []*pflag.FlagSet{cmdBuild.Flags(), cmdBuildImage.Flags()} {
 		flags.StringVarP(&imageTag, "tag", "t", "", "<NAME[:TAG]> to be applied to the image")
 		flags.BoolVarP(&boolQuiet, "quiet", "q", false, "Suppress the verbose output")
+		flags.BoolVarP(&boolForce, "force", "f", false, "Build even if the build context has not changed")
+		flags.BoolVar(&boolAnalyzeContext, "analyze-context", false, "List the largest files and directories in the build context instead of building")
 	}
 
 	cmdImage.AddCommand(cmdBuildImage)
 
 	flags := cmdPullImage.Flags()
 	flags.BoolVarP(&boolAll, "all", "a", false, "Pull all tagged images in the repository. Only the \"latest\" tagged image is pulled by default.")
+	flags.BoolVar(&boolAll, "all-tags", false, "Same as --all")
+	flags.IntVarP(&parallelPulls, "parallel", "p", 4, "Number of images to pull concurrently")
 	cmdImage.AddCommand(cmdPullImage)
 
 	flags = cmdPullImage.Flags()
@@ -141,6 +154,7 @@ func init() {
 
 	flags = cmdShowImageHistory.Flags()
 	flags.BoolVarP(&boolAll, "all", "a", false, "Show all build instructions")
+	flags.BoolVar(&boolDockerfile, "dockerfile", false, "Reconstruct a Dockerfile from the history")
 	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
 	cmdImage.AddCommand(cmdShowImageHistory)
 
@@ -166,62 +180,268 @@ func buildImage(ctx *cobra.Command, args []string) {
 		path = args[0]
 	}
 
+	if boolAnalyzeContext {
+		analyzeBuildContext(ctx, path)
+		return
+	}
+
 	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if _, err := docker.BuildImage(path, imageTag, boolQuiet); err != nil {
+	if _, err := buildImageIfChanged(ctx, docker, path, imageTag, boolQuiet, boolForce); err != nil {
 		log.Fatal(err)
 	}
 }
 
+func buildImageIfChanged(ctx *cobra.Command, docker *api.DockerClient, path, tag string, quiet, force bool) (string, error) {
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	host, err := config.GetHost(hostName)
+	if err != nil {
+		return "", err
+	}
+
+	root, filename, err := api.ResolveDockerfile(path)
+	if err != nil {
+		return "", err
+	}
+
+	dockerfile, err := filepath.Abs(filepath.Join(root, filename))
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := api.BuildContextDigest(path)
+	if err != nil {
+		return "", err
+	}
+
+	cachePath := client.GetBuildCachePath(configPath, host.Name)
+	cache, err := client.LoadBuildCache(cachePath)
+	if err != nil {
+		return "", err
+	}
+
+	if build := cache.GetBuild(dockerfile, tag); !force && (build != nil) && (build.Digest == digest) {
+		image, err := docker.InspectImage(build.Image)
+		if err == nil && tag != "" {
+			var tagged *api.ImageInfo
+			if tagged, err = docker.InspectImage(tag); err == nil && tagged.Id != image.Id {
+				err = fmt.Errorf("%s has been retagged", tag)
+			}
+		}
+		if err == nil {
+			message := fmt.Sprintf("Successfully built %s", Truncate(image.Id, 12))
+			if !quiet {
+				ctx.Printf("Build context of %s has not changed (%s)\n", dockerfile, Truncate(digest, 19))
+				ctx.Println(message)
+			}
+			return message, nil
+		}
+		log.Debugf("Can't reuse the cached build %s: %s", Truncate(build.Image, 12), err)
+	}
+
+	message, err := docker.BuildImage(path, tag, quiet)
+	if err != nil {
+		return "", err
+	}
+
+	// The tag points to the built image, while a quiet build may not report its ID
+	id := tag
+	if id == "" {
+		if _, err := fmt.Sscanf(strings.TrimSpace(message), "Successfully built %s", &id); err != nil {
+			log.Debugf("Can't cache the build without the image ID: %s", message)
+			return message, nil
+		}
+	}
+
+	image, err := docker.InspectImage(id)
+	if err != nil {
+		log.Debugf("Can't cache the build: %s", err)
+		return message, nil
+	}
+
+	cache.SetBuild(&client.Build{
+		Dockerfile: dockerfile,
+		Tag:        tag,
+		Digest:     digest,
+		Image:      image.Id,
+	})
+
+	if err := cache.SaveBuildCache(cachePath); err != nil {
+		log.Warnf("Can't save the build cache: %s", err)
+	}
+
+	return message, nil
+}
+
+// An image with the host where it is, for --all-hosts and --hosts
+type HostImage struct {
+	Host      string
+	api.Image `yaml:",inline"`
+}
+
 func listImages(ctx *cobra.Command, args []string) {
-	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	hosts, err := getTargetHosts()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	images, err := docker.ListImages(boolAll, nil)
+	daemonFilters, clientFilters, err := parseFilters(filterArgs, daemonImageFilters, clientImageFilters)
 	if err != nil {
-		log.Fatal(err)
+		ErrorExit(ctx, err.Error())
 	}
 
-	matchName := ""
-	if len(args) > 0 {
-		matchName = args[0]
+	if err := validateSortField(sortBy, SORT_CREATED, SORT_SIZE, SORT_NAME); err != nil {
+		ErrorExit(ctx, err.Error())
+	}
+
+	if boolAll && ((len(columnNames) > 0) || boolWide) {
+		ErrorExit(ctx, "Can't use --columns or --wide with --all")
+	}
+
+	var (
+		rows    []HostImage
+		dockers = map[string]*api.DockerClient{}
+		infos   = map[string]*api.ImageInfo{}
+	)
+
+	getInfo := func(i int) *api.ImageInfo {
+		key := rows[i].Host + "/" + rows[i].Id
+		if _, exists := infos[key]; !exists {
+			info, err := dockers[rows[i].Host].InspectImage(rows[i].Id)
+			if err != nil {
+				log.Warn(err)
+			}
+			infos[key] = info
+		}
+		return infos[key]
+	}
+
+	columns, err := selectColumns([]Column{
+		{"id", "ID", false, func(i int) string {
+			return Truncate(rows[i].Id, 12)
+		}},
+		{"name", "Name:Tags", false, func(i int) string {
+			name := strings.Join(rows[i].RepoTags, ", ")
+			if name == "<none>:<none>" {
+				name = "<none>"
+			}
+			return FormatNonBreakingString(name)
+		}},
+		{"size", getSizeHeader(), false, func(i int) string {
+			return FormatSizeValue(rows[i].VirtualSize)
+		}},
+		{"created", "Created at", false, func(i int) string {
+			return FormatTimeValue(time.Unix(rows[i].Created, 0))
+		}},
+		{"parent", "Parent", true, func(i int) string {
+			return Truncate(rows[i].ParentId, 12)
+		}},
+		{"labels", "Labels", true, func(i int) string {
+			if info := getInfo(i); info != nil {
+				return FormatLabels(info.Config.Labels)
+			}
+			return ""
+		}},
+	}, columnNames, boolWide)
+	if err != nil {
+		ErrorExit(ctx, err.Error())
 	}
 
-	matchImageByName := func(tags []string, name string) bool {
-		arrName := strings.Split(name, ":")
+	type hostImages struct {
+		docker *api.DockerClient
+		images []api.Image
+	}
+
+	getImages := func(name string) (interface{}, error) {
+		docker, err := client.NewDockerClient(configPath, name, ctx.Out())
+		if err != nil {
+			return nil, err
+		}
+
+		images, err := docker.ListImages(boolAll, daemonFilters)
+		if err != nil {
+			return nil, err
+		}
 
-		for _, tag := range tags {
-			arrTag := strings.Split(tag, ":")
-			if arrTag[0] == arrName[0] {
-				if (len(arrName) < 2) || (arrTag[1] == arrName[1]) {
-					return true
+		if len(args) > 0 {
+			matched := []api.Image{}
+			for _, image := range images {
+				if matchImageByName(image.RepoTags, args[0]) {
+					matched = append(matched, image)
 				}
 			}
+			images = matched
+		}
+
+		images, err = filterImages(docker, images, clientFilters)
+		if err != nil {
+			return nil, err
+		}
+
+		images, err = filterImagesByTime(docker, images, since, before)
+		if err != nil {
+			return nil, err
+		}
+
+		return hostImages{docker, images}, nil
+	}
+
+	var results []HostResult
+	if hosts == nil {
+		value, err := getImages(hostName)
+		if err != nil {
+			log.Fatal(err)
 		}
+		results = append(results, HostResult{hostName, value, nil})
+	} else {
+		results = forEachHost(hosts, getImages)
+	}
 
-		return false
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		value := result.Value.(hostImages)
+		dockers[result.Host] = value.docker
+		for _, image := range value.images {
+			rows = append(rows, HostImage{result.Host, image})
+		}
 	}
 
+	sortImages(rows, sortBy)
+
+	defer func() {
+		if err := reportHostErrors(results); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
 	if boolQuiet {
-		for _, image := range images {
-			if (matchName == "") || matchImageByName(image.RepoTags, matchName) {
-				ctx.Println(Truncate(image.Id, 12))
+		for _, row := range rows {
+			if hosts == nil {
+				ctx.Println(Truncate(row.Id, 12))
+			} else {
+				ctx.Printf("%s\t%s\n", row.Host, Truncate(row.Id, 12))
 			}
 		}
 		return
 	}
 
-	if boolYAML || boolJSON {
-		items := []api.Image{}
-		for _, image := range images {
-			if (matchName == "") || matchImageByName(image.RepoTags, matchName) {
-				items = append(items, image)
+	if boolYAML || boolJSON || (format != "") {
+		var items interface{} = rows
+		if hosts == nil {
+			images := []api.Image{}
+			for _, row := range rows {
+				images = append(images, row.Image)
 			}
+			items = images
 		}
 		if err := FormatPrint(ctx.Out(), items); err != nil {
 			log.Fatal(err)
@@ -229,56 +449,59 @@ func listImages(ctx *cobra.Command, args []string) {
 		return
 	}
 
-	var items [][]string
-
 	if boolAll {
-		roots := []api.Image{}
-		parents := map[string][]api.Image{}
-		for _, image := range images {
-			if image.ParentId == "" {
-				roots = append(roots, image)
-			} else {
-				if children, exists := parents[image.ParentId]; exists {
-					parents[image.ParentId] = append(children, image)
-				} else {
-					children := []api.Image{}
-					parents[image.ParentId] = append(children, image)
+		var items [][]string
+		for _, result := range results {
+			if result.Error != nil {
+				continue
+			}
+			roots, parents := getImageTree(result.Value.(hostImages).images)
+			for _, item := range walkTree(roots, parents, nil, "", [][]string{}) {
+				if hosts != nil {
+					item = append([]string{result.Host}, item...)
 				}
+				items = append(items, item)
 			}
 		}
 
-		items = walkTree(roots, parents, "", items)
-	} else {
-		for _, image := range images {
-			if (matchName == "") || matchImageByName(image.RepoTags, matchName) {
-				name := strings.Join(image.RepoTags, ", ")
-				if name == "<none>:<none>" {
-					name = "<none>"
-				}
-				out := []string{
-					Truncate(image.Id, 12),
-					FormatNonBreakingString(name),
-					FormatFloat(float64(image.VirtualSize) / 1000000),
-					FormatDateTime(time.Unix(image.Created, 0)),
-				}
-				items = append(items, out)
-			}
+		header := []string{
+			"ID",
+			"Name:Tags",
+			getSizeHeader(),
+		}
+		if hosts != nil {
+			header = append([]string{"Host"}, header...)
 		}
+
+		PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+		return
 	}
 
-	header := []string{
-		"ID",
-		"Name:Tags",
-		"Size(MB)",
+	if hosts != nil {
+		columns = append([]Column{{"host", "Host", false, func(i int) string {
+			return rows[i].Host
+		}}}, columns...)
 	}
-	if !boolAll {
-		header = append(header, "Created at")
+
+	PrintColumns(ctx.Out(), columns, len(rows))
+}
+
+func matchImageByName(tags []string, name string) bool {
+	arrName := strings.Split(name, ":")
+
+	for _, tag := range tags {
+		arrTag := strings.Split(tag, ":")
+		if arrTag[0] == arrName[0] {
+			if (len(arrName) < 2) || (arrTag[1] == arrName[1]) {
+				return true
+			}
+		}
 	}
 
-	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+	return false
 }
 
-func walkTree(images []api.Image, parents map[string][]api.Image, prefix string, items [][]string) [][]string {
+func walkTree(images []api.Image, parents map[string][]api.Image, used map[string]bool, prefix string, items [][]string) [][]string {
 	printImage := func(prefix string, image api.Image, isLeaf bool) {
 		name := strings.Join(image.RepoTags, ", ")
 		if name == "<none>:<none>" {
@@ -291,7 +514,10 @@ func walkTree(images []api.Image, parents map[string][]api.Image, prefix string,
 		out := []string{
 			FormatNonBreakingString(fmt.Sprintf("%s %s", prefix, Truncate(image.Id, 12))),
 			FormatNonBreakingString(name),
-			FormatFloat(float64(image.VirtualSize) / 1000000),
+			FormatSizeValue(image.VirtualSize),
+		}
+		if used != nil {
+			out = append(out, FormatBool(used[image.Id], "Yes", ""))
 		}
 		items = append(items, out)
 	}
@@ -303,13 +529,13 @@ func walkTree(images []api.Image, parents map[string][]api.Image, prefix string,
 				subimages, exists := parents[image.Id]
 				printImage(prefix+"└", image, !exists)
 				if exists {
-					items = walkTree(subimages, parents, prefix+" ", items)
+					items = walkTree(subimages, parents, used, prefix+" ", items)
 				}
 			} else {
 				subimages, exists := parents[image.Id]
 				printImage(prefix+"├", image, !exists)
 				if exists {
-					items = walkTree(subimages, parents, prefix+"│", items)
+					items = walkTree(subimages, parents, used, prefix+"│", items)
 				}
 			}
 		}
@@ -318,7 +544,7 @@ func walkTree(images []api.Image, parents map[string][]api.Image, prefix string,
 			subimages, exists := parents[image.Id]
 			printImage(prefix+"└", image, !exists)
 			if exists {
-				items = walkTree(subimages, parents, prefix+" ", items)
+				items = walkTree(subimages, parents, used, prefix+" ", items)
 			}
 		}
 	}
@@ -330,28 +556,44 @@ func pullImage(ctx *cobra.Command, args []string) {
 		ErrorExit(ctx, "Needs an argument <NAME[:TAG]> to pull")
 	}
 
-	reg, name, tag, err := client.ParseRepositoryName(args[0])
-	if err != nil {
-		log.Fatal(err)
-	}
+	var repositories []string
+	for _, arg := range args {
+		ref, err := parseReference(arg)
+		if err != nil {
+			log.Fatal(err)
+		}
 
-	repository := name + ":" + tag
+		repository := ref.String()
 
-	if boolAll {
-		repository = name
+		if boolAll {
+			if ref.Digest != "" {
+				log.Fatalf("Can't pull all tags with a digest: %s", arg)
+			}
+			repository = ref.Name()
+		}
+
+		repositories = append(repositories, repository)
 	}
 
-	if reg != "" {
-		repository = reg + "/" + repository
+	results := pullImages(ctx, repositories, parallelPulls)
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
 	}
 
-	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
-	if err != nil {
-		log.Fatal(err)
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), results); err != nil {
+			log.Fatal(err)
+		}
+	} else if api.DefaultProgressMode != api.PROGRESS_JSON {
+		printPullResults(ctx, results)
 	}
 
-	if _, err := docker.PullImage(repository); err != nil {
-		log.Fatal(err)
+	if failed > 0 {
+		log.Fatalf("Failed to pull %d of %d image(s)", failed, len(results))
 	}
 }
 
@@ -360,15 +602,17 @@ func tagImage(ctx *cobra.Command, args []string) {
 		ErrorExit(ctx, "Needs two arguments <NAME[:TAG]|ID> <NEW-NAME[:TAG]>")
 	}
 
-	reg, name, tag, err := client.ParseRepositoryName(args[1])
+	ref, err := parseReference(args[1])
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if reg != "" {
-		name = reg + "/" + name
+	if ref.Digest != "" {
+		log.Fatalf("Needs a tag, not a digest, to tag as: %s", args[1])
 	}
 
+	name, tag := ref.Name(), ref.Tag
+
 	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
 	if err != nil {
 		log.Fatal(err)
@@ -396,13 +640,22 @@ func showImageHistory(ctx *cobra.Command, args []string) {
 		log.Fatal(err)
 	}
 
+	if boolDockerfile {
+		dockerfile, err := getImageDockerfile(docker, args[0], history)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctx.Print(dockerfile)
+		return
+	}
+
 	// Just reverse history
 	var images api.ImageHistories
 	for i, l := 0, len(history); i < l; i++ {
 		images = append(images, history[l-i-1])
 	}
 
-	if boolYAML || boolJSON {
+	if boolYAML || boolJSON || (format != "") {
 		if err := FormatPrint(ctx.Out(), images); err != nil {
 			log.Fatal(err)
 		}
@@ -412,12 +665,7 @@ func showImageHistory(ctx *cobra.Command, args []string) {
 	var items [][]string
 
 	for _, image := range images {
-		re := regexp.MustCompile("\\s+")
-		createdBy := re.ReplaceAllLiteralString(image.CreatedBy, " ")
-		re = regexp.MustCompile("^/bin/sh -c #\\(nop\\) ")
-		createdBy = re.ReplaceAllLiteralString(createdBy, "")
-		re = regexp.MustCompile("^/bin/sh -c")
-		createdBy = re.ReplaceAllLiteralString(createdBy, "RUN")
+		createdBy := formatCreatedBy(image.CreatedBy)
 		tags := strings.Join(image.Tags, ", ")
 		if !boolAll {
 			createdBy = FormatNonBreakingString(Truncate(createdBy, 50))
@@ -427,8 +675,8 @@ func showImageHistory(ctx *cobra.Command, args []string) {
 			Truncate(image.Id, 12),
 			createdBy,
 			tags,
-			FormatDateTime(time.Unix(image.Created, 0)),
-			FormatFloat(float64(image.Size) / 1000000),
+			FormatTimeValue(time.Unix(image.Created, 0)),
+			FormatSizeValue(image.Size),
 		}
 		items = append(items, out)
 	}
@@ -438,7 +686,7 @@ func showImageHistory(ctx *cobra.Command, args []string) {
 		"Created by",
 		"Name:Tags",
 		"Created at",
-		"Size(MB)",
+		getSizeHeader(),
 	}
 
 	PrintInTable(ctx.Out(), header, items, 20, tablewriter.ALIGN_DEFAULT)
@@ -482,19 +730,21 @@ func pushImage(ctx *cobra.Command, args []string) {
 		ErrorExit(ctx, "Needs an argument <NAME[:TAG]> to push")
 	}
 
-	reg, name, tag, err := client.ParseRepositoryName(args[0])
+	ref, err := parseReference(args[0])
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if len(strings.SplitN(name, "/", 2)) == 1 {
-		log.Fatalf("You cannot push a \"root\" repository. Please rename your repository in <yourname>/%s", name)
+	if ref.Digest != "" {
+		log.Fatalf("Needs a tag, not a digest, to push: %s", args[0])
 	}
 
-	if reg != "" {
-		name = reg + "/" + name
+	if (ref.Registry == "") && (len(strings.SplitN(ref.Path, "/", 2)) == 1) {
+		log.Fatalf("You cannot push a \"root\" repository. Please rename your repository in <yourname>/%s", ref.Path)
 	}
 
+	reg, name, tag := ref.Registry, ref.Name(), ref.Tag
+
 	config, err := client.LoadConfig(configPath)
 	if err != nil {
 		log.Fatal(err)
@@ -569,7 +819,7 @@ func searchImages(ctx *cobra.Command, args []string) {
 		return
 	}
 
-	if boolYAML || boolJSON {
+	if boolYAML || boolJSON || (format != "") {
 		if err := FormatPrint(ctx.Out(), images); err != nil {
 			log.Fatal(err)
 		}
@@ -600,29 +850,69 @@ func searchImages(ctx *cobra.Command, args []string) {
 	PrintInTable(ctx.Out(), header, items, 50, tablewriter.ALIGN_DEFAULT)
 }
 
-func pullImageInSilence(ctx *cobra.Command, name string) error {
-	r, n, t, err := client.ParseRepositoryName(name)
+// Parses the image name with the default registry of the current host
+func parseReference(name string) (*client.Reference, error) {
+	config, err := client.LoadConfig(configPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	name = n + ":" + t
-	if r != "" {
-		name = r + "/" + name
+
+	host, err := config.GetHost(hostName)
+	if err != nil {
+		return nil, err
 	}
 
-	f, err := os.Open(os.DevNull)
+	return host.ParseReference(name)
+}
+
+// Pulls a helper image from the Docker Hub with the client, without any output
+func pullImageInSilence(docker *api.DockerClient, name string) error {
+	ref, err := client.ParseReference(name)
 	if err != nil {
 		return err
 	}
+	name = ref.String()
 
-	docker, err := client.NewDockerClient(configPath, hostName, f)
+	credentials, err := getRegistryCredentials(name)
 	if err != nil {
 		return err
 	}
 
-	if _, err := docker.PullImage(name); err != nil {
+	f, err := os.Open(os.DevNull)
+	if err != nil {
 		return err
 	}
+	defer f.Close()
+
+	if _, err := docker.WithOutput(f).PullImage(name, credentials); err != nil {
+		return fmt.Errorf("Can't pull %s, which is required: %s", name, err)
+	}
 
 	return nil
 }
+
+// Returns the stored credentials of the registry for the image, or "" if not logged in
+func getRegistryCredentials(name string) (string, error) {
+	ref, err := client.ParseReference(name)
+	if err != nil {
+		return "", err
+	}
+
+	reg := ref.Registry
+
+	if reg == "" {
+		reg = client.INDEX_SERVER
+	}
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	registry, err := config.GetRegistry(reg)
+	if err != nil {
+		return "", nil
+	}
+
+	return registry.Credentials, nil
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/client"
+)
+
+var (
+	importSources []string
+)
+
+var cmdImportHosts = &cobra.Command{
+	Use:   "import",
+	Short: "Import hosts from docker-machine, boot2docker and environment variables",
+	Long:  APP_NAME + " host import - Import hosts from docker-machine, boot2docker and environment variables",
+	Run:   importHosts,
+}
+
+func init() {
+	flags := cmdImportHosts.Flags()
+	flags.StringSliceVar(&importSources, "from", []string{client.IMPORT_MACHINE, client.IMPORT_BOOT2DOCKER, client.IMPORT_ENV},
+		"Sources to import from: machine, boot2docker and env")
+	flags.BoolVar(&boolDryRun, "dry-run", false, "Only show what would be imported")
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdHost.AddCommand(cmdImportHosts)
+}
+
+func importHosts(ctx *cobra.Command, args []string) {
+	defer lockConfig().Unlock()
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hosts, err := client.DiscoverHosts(os.Getenv("HOME"), importSources)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(hosts) == 0 {
+		log.Info("No hosts found to import")
+		return
+	}
+
+	var (
+		items   [][]string
+		changed = false
+	)
+
+	for _, imported := range hosts {
+		action := config.ImportHost(imported.Host)
+		if action != client.HOST_UNCHANGED {
+			changed = true
+		}
+
+		out := []string{
+			action,
+			imported.Host.Name,
+			imported.Host.URL,
+			FormatBool(imported.Host.TLS, "YES", ""),
+			imported.Source,
+		}
+		items = append(items, out)
+	}
+
+	header := []string{
+		"Action",
+		"Name",
+		"URL",
+		"TLS",
+		"Source",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+
+	if boolDryRun || !changed {
+		return
+	}
+
+	if err := config.SaveConfig(configPath); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+const (
+	LINT_UNKNOWN_INSTRUCTION = "unknown-instruction"
+	LINT_FROM_TAG            = "from-tag"
+	LINT_ADD_REMOTE          = "add-remote"
+	LINT_APT_GET_CLEANUP     = "apt-get-cleanup"
+	LINT_SOURCE_EXCLUDED     = "source-excluded"
+	LINT_SOURCE_MISSING      = "source-missing"
+	LINT_MULTIPLE_CMD        = "multiple-cmd"
+	LINT_MULTIPLE_ENTRYPOINT = "multiple-entrypoint"
+)
+
+type LintProblem struct {
+	Line    int
+	Rule    string
+	Message string
+}
+
+type LintProblems []LintProblem
+
+func (problems LintProblems) Len() int {
+	return len(problems)
+}
+
+func (problems LintProblems) Swap(i, j int) {
+	problems[i], problems[j] = problems[j], problems[i]
+}
+
+func (problems LintProblems) Less(i, j int) bool {
+	return problems[i].Line < problems[j].Line
+}
+
+var cmdLintImage = &cobra.Command{
+	Use:   "lint [PATH/TO/DOCKERFILE]",
+	Short: "Check a Dockerfile for common mistakes",
+	Long:  APP_NAME + " image lint - Check a Dockerfile for common mistakes",
+	Run:   lintImage,
+}
+
+func init() {
+	flags := cmdLintImage.Flags()
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdImage.AddCommand(cmdLintImage)
+}
+
+func lintImage(ctx *cobra.Command, args []string) {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	root, filename, err := api.ResolveDockerfile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	problems, err := lintDockerfile(root, filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), problems); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		var items [][]string
+		for _, problem := range problems {
+			out := []string{
+				strconv.Itoa(problem.Line),
+				problem.Rule,
+				problem.Message,
+			}
+			items = append(items, out)
+		}
+
+		header := []string{
+			"Line",
+			"Rule",
+			"Message",
+		}
+
+		PrintInTable(ctx.Out(), header, items, 80, tablewriter.ALIGN_DEFAULT)
+	}
+
+	if len(problems) > 0 {
+		log.Fatalf("Found %d problem(s) in %s", len(problems), filepath.Join(root, filename))
+	}
+}
+
+func lintDockerfile(root, filename string) (LintProblems, error) {
+	instructions, err := api.ParseDockerfileFromPath(filepath.Join(root, filename))
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := api.ReadDockerignore(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		problems LintProblems
+		lastCmd  = 0
+		lastEnt  = 0
+	)
+
+	report := func(instruction api.Instruction, rule, format string, a ...interface{}) {
+		problems = append(problems, LintProblem{
+			Line:    instruction.Line,
+			Rule:    rule,
+			Message: fmt.Sprintf(format, a...),
+		})
+	}
+
+	for _, instruction := range instructions {
+		if !instruction.IsKnown() {
+			report(instruction, LINT_UNKNOWN_INSTRUCTION, "Unknown instruction: %s", instruction.Command)
+			continue
+		}
+
+		switch instruction.Command {
+		case "FROM":
+			image := instruction.Args[0]
+			if image == "scratch" {
+				break
+			}
+			name := image[strings.LastIndex(image, "/")+1:]
+			if !strings.ContainsAny(name, ":@") {
+				report(instruction, LINT_FROM_TAG, "%s has no tag; the implicit \"latest\" may change at any time", image)
+			}
+		case "RUN":
+			if strings.Contains(instruction.Value, "apt-get") &&
+				strings.Contains(instruction.Value, "install") &&
+				!strings.Contains(instruction.Value, "/var/lib/apt/lists") {
+				report(instruction, LINT_APT_GET_CLEANUP, "apt-get install without removing /var/lib/apt/lists/* in the same RUN")
+			}
+		case "CMD":
+			if lastCmd > 0 {
+				report(instruction, LINT_MULTIPLE_CMD, "CMD is also at line %d; only the last one takes effect", lastCmd)
+			}
+			lastCmd = instruction.Line
+		case "ENTRYPOINT":
+			if lastEnt > 0 {
+				report(instruction, LINT_MULTIPLE_ENTRYPOINT, "ENTRYPOINT is also at line %d; only the last one takes effect", lastEnt)
+			}
+			lastEnt = instruction.Line
+		case "ADD", "COPY":
+			if len(instruction.Args) < 2 {
+				break
+			}
+			for _, src := range instruction.Args[:len(instruction.Args)-1] {
+				if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+					if instruction.Command == "ADD" {
+						report(instruction, LINT_ADD_REMOTE, "ADD of a remote URL %s is neither cached nor verified; use RUN with curl or wget instead", src)
+					}
+					continue
+				}
+
+				rule, message := lintContextSource(root, src, excludes)
+				if rule != "" {
+					report(instruction, rule, "%s source %s %s", instruction.Command, src, message)
+				}
+			}
+		}
+	}
+
+	sort.Stable(problems)
+
+	return problems, nil
+}
+
+func lintContextSource(root, src string, excludes []string) (string, string) {
+	src = filepath.Clean(strings.TrimPrefix(src, "/"))
+	if (src == "..") || strings.HasPrefix(src, ".."+string(filepath.Separator)) {
+		return LINT_SOURCE_MISSING, "is outside of the build context"
+	}
+
+	matches, err := filepath.Glob(filepath.Join(root, src))
+	if err != nil || len(matches) == 0 {
+		return LINT_SOURCE_MISSING, "is not found in the build context"
+	}
+
+	for _, match := range matches {
+		relFilePath, err := filepath.Rel(root, match)
+		if err != nil {
+			continue
+		}
+		if !isExcludedFromContext(relFilePath, excludes) {
+			return "", ""
+		}
+	}
+
+	return LINT_SOURCE_EXCLUDED, "is excluded by " + api.DOCKERIGNORE
+}
+
+func isExcludedFromContext(relFilePath string, excludes []string) bool {
+	for path := relFilePath; (path != ".") && (path != string(filepath.Separator)); path = filepath.Dir(path) {
+		if excluded, _ := api.MatchExcludes(path, excludes); excluded {
+			return true
+		}
+	}
+	return false
+}
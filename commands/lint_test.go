@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLintDockerfile(t *testing.T) {
+	root, err := ioutil.TempDir("", "talk2docker")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(root)
+
+	ioutil.WriteFile(filepath.Join(root, "app.tar"), []byte{}, 0600)
+	ioutil.WriteFile(filepath.Join(root, "secret.txt"), []byte{}, 0600)
+	ioutil.WriteFile(filepath.Join(root, ".dockerignore"), []byte("secret.txt\n"), 0600)
+
+	tests := []struct {
+		dockerfile string
+		expected   []string // Line:Rule
+	}{
+		{"FROM debian:wheezy\nCMD [\"/bin/bash\"]\n", nil},
+		{"FROM scratch\nADD app.tar /\n", nil},
+		{"FROM busybox@sha256:0123456789abcdef0123456789abcdef\n", nil},
+		{"FROM debian\n", []string{"1:" + LINT_FROM_TAG}},
+		{"FROM localhost:5000/debian\n", []string{"1:" + LINT_FROM_TAG}},
+		{"FROM debian:wheezy\nADD https://example.com/app.tar /\n", []string{"2:" + LINT_ADD_REMOTE}},
+		{"FROM debian:wheezy\nCOPY https://example.com/app.tar /\n", nil},
+		{"FROM debian:wheezy\nRUN apt-get update && apt-get install -y curl\n", []string{"2:" + LINT_APT_GET_CLEANUP}},
+		{"FROM debian:wheezy\nRUN apt-get update && apt-get install -y curl && rm -rf /var/lib/apt/lists/*\n", nil},
+		{"FROM debian:wheezy\nCOPY secret.txt /\n", []string{"2:" + LINT_SOURCE_EXCLUDED}},
+		{"FROM debian:wheezy\nCOPY missing.txt /\n", []string{"2:" + LINT_SOURCE_MISSING}},
+		{"FROM debian:wheezy\nADD ../outside.txt /\n", []string{"2:" + LINT_SOURCE_MISSING}},
+		{"FROM debian:wheezy\nCMD [\"a\"]\nCMD [\"b\"]\n", []string{"3:" + LINT_MULTIPLE_CMD}},
+		{"FROM debian:wheezy\nENTRYPOINT [\"a\"]\nENTRYPOINT [\"b\"]\n", []string{"3:" + LINT_MULTIPLE_ENTRYPOINT}},
+		{"FROM debian:wheezy\nFOO bar\n", []string{"2:" + LINT_UNKNOWN_INSTRUCTION}},
+	}
+
+	for _, test := range tests {
+		ioutil.WriteFile(filepath.Join(root, "Dockerfile"), []byte(test.dockerfile), 0600)
+
+		problems, err := lintDockerfile(root, "Dockerfile")
+		if err != nil {
+			t.Fatalf("%q: %v", test.dockerfile, err)
+		}
+
+		var actual []string
+		for _, problem := range problems {
+			actual = append(actual, strconv.Itoa(problem.Line)+":"+problem.Rule)
+		}
+		if strings.Join(actual, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%q: got %v\nwant %v", test.dockerfile, actual, test.expected)
+		}
+	}
+}
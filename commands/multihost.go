@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+
+	"github.com/ailispaw/talk2docker/client"
+)
+
+const (
+	HOST_TIMEOUT = 30 * time.Second
+)
+
+var (
+	targetHosts []string
+)
+
+type HostResult struct {
+	Host  string
+	Value interface{}
+	Error error
+}
+
+func addMultiHostFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&boolAllHosts, "all-hosts", false, "Run on all hosts in the configuration file")
+	flags.StringSliceVar(&targetHosts, "hosts", []string{}, "Run on the given hosts, e.g. --hosts a,b,c")
+}
+
+// Returns the hosts to run on by --all-hosts or --hosts, or nil for the current host only
+func getTargetHosts() ([]string, error) {
+	if !boolAllHosts && (len(targetHosts) == 0) {
+		return nil, nil
+	}
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if boolAllHosts {
+		names := []string{}
+		for _, host := range config.Hosts {
+			names = append(names, host.Name)
+		}
+		return names, nil
+	}
+
+	names := []string{}
+	for _, name := range targetHosts {
+		host, err := config.GetHost(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, host.Name)
+	}
+	return names, nil
+}
+
+// Runs fn on the hosts concurrently, each within its own timeout,
+// and returns the results in the order of the hosts
+func forEachHost(names []string, fn func(name string) (interface{}, error)) []HostResult {
+	return forEachHostWithTimeouts(names, getHostTimeouts(names), fn)
+}
+
+func forEachHostWithin(names []string, timeout time.Duration, fn func(name string) (interface{}, error)) []HostResult {
+	timeouts := map[string]time.Duration{}
+	for _, name := range names {
+		timeouts[name] = timeout
+	}
+	return forEachHostWithTimeouts(names, timeouts, fn)
+}
+
+// Returns the timeout of each host in the configuration file
+func getHostTimeouts(names []string) map[string]time.Duration {
+	timeouts := map[string]time.Duration{}
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		return timeouts
+	}
+
+	for _, name := range names {
+		host, err := config.GetHost(name)
+		if err != nil {
+			continue
+		}
+		if timeout, err := host.GetTimeout(); err == nil {
+			timeouts[name] = timeout
+		}
+	}
+	return timeouts
+}
+
+// Runs fn on the hosts concurrently, where a host without its timeout has HOST_TIMEOUT
+func forEachHostWithTimeouts(names []string, timeouts map[string]time.Duration, fn func(name string) (interface{}, error)) []HostResult {
+	results := make([]HostResult, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		timeout, ok := timeouts[name]
+		if !ok {
+			timeout = HOST_TIMEOUT
+		}
+
+		wg.Add(1)
+		go func(i int, name string, timeout time.Duration) {
+			defer wg.Done()
+
+			done := make(chan HostResult, 1)
+			go func() {
+				value, err := fn(name)
+				done <- HostResult{name, value, err}
+			}()
+
+			select {
+			case result := <-done:
+				results[i] = result
+			case <-time.After(timeout):
+				results[i] = HostResult{name, nil, fmt.Errorf("Timed out after %s", timeout)}
+			}
+		}(i, name, timeout)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Logs the errors of unreachable hosts, and returns an error if any
+func reportHostErrors(results []HostResult) error {
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			log.Errorf("%s: %s", result.Host, result.Error)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.New("Error: failed on one or more hosts")
+	}
+	return nil
+}
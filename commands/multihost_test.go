@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestForEachHost(t *testing.T) {
+	names := []string{"slow", "broken", "fast"}
+
+	results := forEachHostWithin(names, HOST_TIMEOUT, func(name string) (interface{}, error) {
+		switch name {
+		case "slow":
+			time.Sleep(10 * time.Millisecond)
+		case "broken":
+			return nil, errors.New("Connection refused")
+		}
+		return name + "-value", nil
+	})
+
+	if len(results) != len(names) {
+		t.Fatalf("got %v\nwant %v", len(results), len(names))
+	}
+	for i, result := range results {
+		if result.Host != names[i] {
+			t.Errorf("got %v\nwant %v", result.Host, names[i])
+		}
+	}
+	if results[0].Value != "slow-value" || results[2].Value != "fast-value" {
+		t.Errorf("got %v\nwant %v", results, "values in the order of the hosts")
+	}
+	if results[1].Error == nil {
+		t.Errorf("%v", "An unreachable host should be an error.")
+	}
+
+	if err := reportHostErrors(results); err == nil {
+		t.Errorf("%v", "An unreachable host should be reported.")
+	}
+}
+
+func TestForEachHostWithTimeouts(t *testing.T) {
+	names := []string{"slow", "patient"}
+	timeouts := map[string]time.Duration{
+		"slow":    5 * time.Millisecond,
+		"patient": time.Second,
+	}
+
+	results := forEachHostWithTimeouts(names, timeouts, func(name string) (interface{}, error) {
+		time.Sleep(50 * time.Millisecond)
+		return name + "-value", nil
+	})
+
+	if results[0].Error == nil {
+		t.Errorf("got %v\nwant %v", results[0], "a timeout")
+	}
+	if (results[1].Error != nil) || (results[1].Value != "patient-value") {
+		t.Errorf("got %v\nwant %v", results[1], "patient-value")
+	}
+}
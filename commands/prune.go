@@ -0,0 +1,308 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+var (
+	boolDryRun, boolYes bool
+
+	pruneUntil string
+)
+
+type PruneCandidate struct {
+	ID      string
+	Name    string
+	Created int64
+	Size    int64
+}
+
+var cmdPruneImages = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove dangling images",
+	Long:  APP_NAME + " image prune - Remove dangling images",
+	Run:   pruneImages,
+}
+
+var cmdPruneContainers = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove exited containers",
+	Long:  APP_NAME + " container prune - Remove exited containers",
+	Run:   pruneContainers,
+}
+
+func init() {
+	flags := cmdPruneImages.Flags()
+	flags.BoolVarP(&boolAll, "all", "a", false, "Remove all images not used by any container, not only dangling ones")
+	flags.BoolVar(&boolDryRun, "dry-run", false, "Only show what would be removed")
+	flags.BoolVarP(&boolYes, "yes", "y", false, "Remove without confirmation")
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdImage.AddCommand(cmdPruneImages)
+
+	flags = cmdPruneContainers.Flags()
+	flags.StringVar(&pruneUntil, "until", "", "Only remove containers created before the given duration ago, e.g. 24h")
+	flags.BoolVar(&boolDryRun, "dry-run", false, "Only show what would be removed")
+	flags.BoolVarP(&boolYes, "yes", "y", false, "Remove without confirmation")
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdContainer.AddCommand(cmdPruneContainers)
+}
+
+func pruneImages(ctx *cobra.Command, args []string) {
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var images []api.Image
+	if boolAll {
+		images, err = getUnusedImages(docker)
+	} else {
+		images, err = docker.ListImages(false, map[string][]string{"dangling": {"true"}})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var (
+		candidates []PruneCandidate
+		repoTags   = map[string][]string{}
+	)
+	for _, image := range images {
+		for _, tag := range image.RepoTags {
+			if tag != "<none>:<none>" {
+				repoTags[image.Id] = append(repoTags[image.Id], tag)
+			}
+		}
+		name := "<none>:<none>"
+		if len(repoTags[image.Id]) > 0 {
+			name = strings.Join(repoTags[image.Id], ", ")
+		}
+		candidates = append(candidates, PruneCandidate{
+			ID:      image.Id,
+			Name:    name,
+			Created: image.Created,
+			Size:    image.VirtualSize,
+		})
+	}
+
+	// The daemon refuses to remove an image by ID while it has more than one tag,
+	// and removing the last tag removes the image
+	prune(ctx, "image", candidates, func(id string) error {
+		if len(repoTags[id]) == 0 {
+			return docker.RemoveImage(id, false, false)
+		}
+		for _, tag := range repoTags[id] {
+			if err := docker.RemoveImage(tag, false, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Returns the top-level images which no container is created from, directly or via their children
+func getUnusedImages(docker *api.DockerClient) ([]api.Image, error) {
+	images, err := docker.ListImages(false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := getUsedImages(docker)
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []api.Image
+	for _, image := range images {
+		if !used[image.Id] {
+			unused = append(unused, image)
+		}
+	}
+	return unused, nil
+}
+
+// Returns the IDs of the images which containers are created from, and their ancestors
+func getUsedImages(docker *api.DockerClient) (map[string]bool, error) {
+	allImages, err := docker.ListImages(true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := map[string]string{}
+	for _, image := range allImages {
+		parents[image.Id] = image.ParentId
+	}
+
+	containers, err := docker.ListContainers(true, false, 0, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	for _, container := range containers {
+		containerInfo, err := docker.InspectContainer(container.Id)
+		if err != nil {
+			return nil, err
+		}
+		for id := containerInfo.Image; (id != "") && !used[id]; id = parents[id] {
+			used[id] = true
+		}
+	}
+	return used, nil
+}
+
+func pruneContainers(ctx *cobra.Command, args []string) {
+	var until time.Time
+	if pruneUntil != "" {
+		duration, err := time.ParseDuration(pruneUntil)
+		if err != nil {
+			log.Fatalf("Invalid duration: %s", pruneUntil)
+		}
+		until = time.Now().Add(-duration)
+	}
+
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	containers, err := docker.ListContainers(true, true, 0, "", "", map[string][]string{"status": {"exited"}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var candidates []PruneCandidate
+	for _, container := range containers {
+		if !until.IsZero() && !time.Unix(container.Created, 0).Before(until) {
+			continue
+		}
+		names := []string{}
+		for _, name := range container.Names {
+			names = append(names, strings.TrimPrefix(name, "/"))
+		}
+		candidates = append(candidates, PruneCandidate{
+			ID:      container.Id,
+			Name:    strings.Join(names, ", "),
+			Created: container.Created,
+			Size:    container.SizeRw,
+		})
+	}
+
+	prune(ctx, "container", candidates, func(id string) error {
+		return docker.RemoveContainer(id, false)
+	})
+}
+
+func prune(ctx *cobra.Command, kind string, candidates []PruneCandidate, remove func(id string) error) {
+	structured := boolYAML || boolJSON || (format != "")
+
+	if len(candidates) == 0 {
+		if structured {
+			if err := FormatPrint(ctx.Out(), []PruneCandidate{}); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			ctx.Printf("No %ss to remove\n", kind)
+		}
+		return
+	}
+
+	var total int64
+	for _, candidate := range candidates {
+		total += candidate.Size
+	}
+
+	if structured {
+		if boolDryRun || !boolYes {
+			if err := FormatPrint(ctx.Out(), candidates); err != nil {
+				log.Fatal(err)
+			}
+			if !boolDryRun {
+				log.Warnf("No %ss removed: needs --yes to remove them with -J, -Y or --format", kind)
+			}
+			return
+		}
+	} else {
+		var items [][]string
+		for _, candidate := range candidates {
+			out := []string{
+				Truncate(candidate.ID, 12),
+				candidate.Name,
+				FormatDateTime(time.Unix(candidate.Created, 0)),
+				FormatFloat(float64(candidate.Size) / 1000000),
+			}
+			items = append(items, out)
+		}
+
+		header := []string{
+			"ID",
+			"Name",
+			"Created at",
+			"Size(MB)",
+		}
+
+		PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+
+		ctx.Printf("%d %s(s), %s MB in total\n", len(candidates), kind, FormatFloat(float64(total)/1000000))
+	}
+
+	if boolDryRun {
+		return
+	}
+
+	if !boolYes && !confirm(ctx, fmt.Sprintf("Remove %d %s(s)?", len(candidates), kind)) {
+		return
+	}
+
+	var (
+		removed   = []PruneCandidate{}
+		reclaimed int64
+		failed    int
+	)
+	for _, candidate := range candidates {
+		if err := remove(candidate.ID); err != nil {
+			log.Error(err)
+			failed++
+			continue
+		}
+		removed = append(removed, candidate)
+		reclaimed += candidate.Size
+	}
+
+	// Prints the removed ones in the requested format
+	if structured {
+		if err := FormatPrint(ctx.Out(), removed); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		ctx.Printf("Reclaimed space: %s MB\n", FormatFloat(float64(reclaimed)/1000000))
+	}
+
+	if failed > 0 {
+		log.Fatalf("Failed to remove %d %s(s)", failed, kind)
+	}
+}
+
+func confirm(ctx *cobra.Command, prompt string) bool {
+	ctx.Printf("%s [y/N]: ", prompt)
+
+	reader := bufio.NewReader(os.Stdin)
+	line, _, err := reader.ReadLine()
+	if err != nil {
+		return false
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(string(line)))
+	return (answer == "y") || (answer == "yes")
+}
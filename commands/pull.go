@@ -0,0 +1,296 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+const (
+	PULL_BAR_WIDTH = 30
+)
+
+type PullResult struct {
+	Name     string
+	ID       string
+	Tags     []string `json:",omitempty" yaml:",omitempty"` // pulled with --all
+	Digest   string
+	Duration time.Duration
+	Error    string
+}
+
+// Shows a line of the aggregated progress per image
+type pullBoard struct {
+	sync.Mutex
+
+	out   io.Writer
+	tty   bool
+	names []string
+	lines []string
+	steps []int
+	drawn bool
+}
+
+func newPullBoard(out io.Writer, names []string) *pullBoard {
+	board := &pullBoard{
+		out:   out,
+		tty:   api.ResolveProgressMode(api.DefaultProgressMode, out) == api.PROGRESS_TTY,
+		names: names,
+		lines: make([]string, len(names)),
+		steps: make([]int, len(names)),
+	}
+	for i := range names {
+		board.lines[i] = "Waiting"
+		board.steps[i] = -1
+	}
+	return board
+}
+
+func (board *pullBoard) update(i int, progress api.PullProgress) {
+	line := progress.Status
+	percent := progress.Percentage()
+	if percent >= 0 {
+		if board.tty {
+			filled := percent * PULL_BAR_WIDTH / 100
+			line = fmt.Sprintf("[%s>%s] %3d%% %.3f/%.3f MB (%d/%d layers)",
+				strings.Repeat("=", filled), strings.Repeat(" ", PULL_BAR_WIDTH-filled), percent,
+				float64(progress.Current)/1000000, float64(progress.Total)/1000000,
+				progress.Done, progress.Layers)
+		} else {
+			line = fmt.Sprintf("%d%% %.3f/%.3f MB (%d/%d layers)", percent,
+				float64(progress.Current)/1000000, float64(progress.Total)/1000000,
+				progress.Done, progress.Layers)
+		}
+	}
+
+	board.Lock()
+	defer board.Unlock()
+
+	if board.tty {
+		board.lines[i] = line
+		board.draw()
+		return
+	}
+
+	// Print only at each status change or step of percentage for logs
+	step := -1
+	if percent >= 0 {
+		step = (percent / api.PLAIN_PROGRESS_STEP) * api.PLAIN_PROGRESS_STEP
+	}
+	if (percent >= 0) && (step == board.steps[i]) {
+		return
+	}
+	if (percent < 0) && (line == board.lines[i]) {
+		return
+	}
+	board.lines[i] = line
+	board.steps[i] = step
+	fmt.Fprintf(board.out, "%s: %s\n", board.names[i], line)
+}
+
+func (board *pullBoard) finish(i int, result *PullResult) {
+	line := fmt.Sprintf("Pulled in %s", formatPullDuration(result.Duration))
+	if result.Error != "" {
+		line = "Error: " + result.Error
+	}
+
+	board.Lock()
+	defer board.Unlock()
+
+	board.lines[i] = line
+	if board.tty {
+		board.draw()
+		return
+	}
+	fmt.Fprintf(board.out, "%s: %s\n", board.names[i], line)
+}
+
+func (board *pullBoard) draw() {
+	width := 0
+	for _, name := range board.names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+
+	if board.drawn {
+		// <ESC>[{n}A = move cursor up n rows
+		fmt.Fprintf(board.out, "%c[%dA", 27, len(board.lines))
+	}
+	for i, line := range board.lines {
+		// <ESC>[2K = erase the entire line
+		fmt.Fprintf(board.out, "%c[2K%-*s %s\n", 27, width, board.names[i], line)
+	}
+	board.drawn = true
+}
+
+type syncWriter struct {
+	sync.Mutex
+	out io.Writer
+}
+
+func (writer *syncWriter) Write(p []byte) (int, error) {
+	writer.Lock()
+	defer writer.Unlock()
+	return writer.out.Write(p)
+}
+
+func pullImages(ctx *cobra.Command, names []string, parallel int) []PullResult {
+	// Keep the progress out of the structured output
+	progressOut := ctx.Out()
+	if boolYAML || boolJSON || (format != "") {
+		progressOut = os.Stderr
+	}
+
+	var (
+		results = make([]PullResult, len(names))
+		board   = newPullBoard(progressOut, names)
+		out     = &syncWriter{out: progressOut}
+		queue   = make(chan int)
+		wg      sync.WaitGroup
+	)
+
+	if parallel < 1 {
+		parallel = 1
+	}
+	if parallel > len(names) {
+		parallel = len(names)
+	}
+
+	for n := 0; n < parallel; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = pullImageWithProgress(board, out, i, names[i])
+				if api.DefaultProgressMode != api.PROGRESS_JSON {
+					board.finish(i, &results[i])
+				}
+			}
+		}()
+	}
+
+	for i := range names {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+func pullImageWithProgress(board *pullBoard, out io.Writer, i int, name string) PullResult {
+	result := PullResult{
+		Name: name,
+	}
+
+	start := time.Now()
+
+	credentials, err := getRegistryCredentials(name)
+	if err != nil {
+		result.Error = err.Error()
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	docker, err := client.NewDockerClient(configPath, hostName, out)
+	if err != nil {
+		result.Error = err.Error()
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	var renderer *api.AggregateRenderer
+	if api.DefaultProgressMode != api.PROGRESS_JSON {
+		renderer = api.NewAggregateRenderer(func(progress api.PullProgress) {
+			board.update(i, progress)
+		})
+		docker.Progress = func(out io.Writer) api.ProgressRenderer {
+			return renderer
+		}
+	}
+
+	if _, err := docker.PullImage(name, credentials); err != nil {
+		result.Error = err.Error()
+		result.Duration = time.Since(start)
+		return result
+	}
+	result.Duration = time.Since(start)
+
+	if renderer != nil {
+		result.Digest = renderer.Digest
+	}
+
+	// All the tags of the repository have their own IDs
+	if boolAll {
+		if images, err := docker.ListImages(false, nil); err == nil {
+			for _, image := range images {
+				for _, tag := range image.RepoTags {
+					if strings.HasPrefix(tag, name+":") {
+						result.Tags = append(result.Tags, tag)
+					}
+				}
+			}
+			sort.Strings(result.Tags)
+		}
+		return result
+	}
+
+	if image, err := docker.InspectImage(name); err == nil {
+		result.ID = image.Id
+	}
+
+	return result
+}
+
+func printPullResults(ctx *cobra.Command, results []PullResult) {
+	var items [][]string
+	for _, result := range results {
+		id := "-"
+		if result.ID != "" {
+			id = Truncate(result.ID, 12)
+		} else if len(result.Tags) > 0 {
+			id = fmt.Sprintf("%d tag(s)", len(result.Tags))
+		}
+		digest := "-"
+		if result.Digest != "" {
+			digest = result.Digest
+		}
+		status := "Pulled"
+		if result.Error != "" {
+			status = "Failed"
+		}
+		out := []string{
+			result.Name,
+			id,
+			digest,
+			formatPullDuration(result.Duration),
+			status,
+		}
+		items = append(items, out)
+	}
+
+	header := []string{
+		"Name",
+		"ID",
+		"Digest",
+		"Duration",
+		"Status",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+}
+
+func formatPullDuration(duration time.Duration) string {
+	return fmt.Sprintf("%.1fs", duration.Seconds())
+}
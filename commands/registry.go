@@ -3,6 +3,7 @@ package commands
 import (
 	"bufio"
 	"os"
+	"strings"
 
 	"github.com/howeyc/gopass"
 	log "github.com/sirupsen/logrus"
@@ -47,6 +48,28 @@ var cmdLogoutRegistry = &cobra.Command{
 	Run:     logoutRegistry,
 }
 
+var cmdListRepositories = &cobra.Command{
+	Use:   "repos [REGISTRY]",
+	Short: "List repositories in a registry",
+	Long:  APP_NAME + " registry repos - List repositories in a registry",
+	Run:   listRepositories,
+}
+
+var cmdListTags = &cobra.Command{
+	Use:   "tags <NAME>",
+	Short: "List tags of a repository in a registry",
+	Long:  APP_NAME + " registry tags - List tags of a repository in a registry",
+	Run:   listTags,
+}
+
+var cmdDeleteTag = &cobra.Command{
+	Use:     "delete <NAME:TAG>",
+	Aliases: []string{"del"},
+	Short:   "Delete a tag from a registry",
+	Long:    APP_NAME + " registry delete - Delete a tag from a registry",
+	Run:     deleteTag,
+}
+
 func init() {
 	flags := cmdListRegistries.Flags()
 	flags.BoolVarP(&boolQuiet, "quiet", "q", false, "Only display numeric IDs")
@@ -56,6 +79,16 @@ func init() {
 	cmdRegistry.AddCommand(cmdLoginRegistry)
 
 	cmdRegistry.AddCommand(cmdLogoutRegistry)
+
+	flags = cmdListRepositories.Flags()
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdRegistry.AddCommand(cmdListRepositories)
+
+	flags = cmdListTags.Flags()
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	cmdRegistry.AddCommand(cmdListTags)
+
+	cmdRegistry.AddCommand(cmdDeleteTag)
 }
 
 func listRegistries(ctx *cobra.Command, args []string) {
@@ -71,7 +104,7 @@ func listRegistries(ctx *cobra.Command, args []string) {
 		return
 	}
 
-	if boolYAML || boolJSON {
+	if boolYAML || boolJSON || (format != "") {
 		if err := FormatPrint(ctx.Out(), config.Registries); err != nil {
 			log.Fatal(err)
 		}
@@ -105,6 +138,8 @@ func loginRegistry(ctx *cobra.Command, args []string) {
 		reg = args[0]
 	}
 
+	defer lockConfig().Unlock()
+
 	config, err := client.LoadConfig(configPath)
 	if err != nil {
 		log.Fatal(err)
@@ -185,6 +220,8 @@ func logoutRegistry(ctx *cobra.Command, args []string) {
 		reg = args[0]
 	}
 
+	defer lockConfig().Unlock()
+
 	config, err := client.LoadConfig(configPath)
 	if err != nil {
 		log.Fatal(err)
@@ -205,3 +242,129 @@ func logoutRegistry(ctx *cobra.Command, args []string) {
 
 	listRegistries(ctx, args)
 }
+
+func getRegistryClient(reg string) (*client.RegistryClient, error) {
+	if reg == "" {
+		reg = client.INDEX_SERVER
+	}
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	registry, _ := config.GetRegistry(reg)
+
+	return client.NewRegistryClient(registry)
+}
+
+// Returns the registry client and the repository name in the registry
+func getRepositoryClient(name string) (*client.RegistryClient, string, string, error) {
+	ref, err := client.ParseReference(name)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	path := ref.Path
+	if (ref.Registry == "") && !strings.Contains(path, "/") {
+		path = "library/" + path
+	}
+
+	registry, err := getRegistryClient(ref.Registry)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return registry, path, ref.TagOrDigest(), nil
+}
+
+func listRepositories(ctx *cobra.Command, args []string) {
+	reg := ""
+	if len(args) > 0 {
+		reg = args[0]
+	}
+
+	registry, err := getRegistryClient(reg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	repositories, err := registry.ListRepositories()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), repositories); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var items [][]string
+	for _, repository := range repositories {
+		items = append(items, []string{repository})
+	}
+
+	header := []string{
+		"Repository",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+}
+
+func listTags(ctx *cobra.Command, args []string) {
+	if len(args) < 1 {
+		ErrorExit(ctx, "Needs an argument <NAME> to list tags")
+	}
+
+	registry, name, _, err := getRepositoryClient(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tags, err := registry.ListTags(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if boolYAML || boolJSON || (format != "") {
+		if err := FormatPrint(ctx.Out(), tags); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var items [][]string
+	for _, tag := range tags {
+		items = append(items, []string{name, tag})
+	}
+
+	header := []string{
+		"Repository",
+		"Tag",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+}
+
+func deleteTag(ctx *cobra.Command, args []string) {
+	if len(args) < 1 {
+		ErrorExit(ctx, "Needs an argument <NAME:TAG> to delete")
+	}
+
+	if !strings.Contains(args[0][strings.LastIndex(args[0], "/")+1:], ":") {
+		ErrorExit(ctx, "Needs a tag explicitly to delete, <NAME:TAG>")
+	}
+
+	registry, name, tag, err := getRepositoryClient(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := registry.DeleteTag(name, tag); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx.Printf("Deleted %s:%s\n", name, tag)
+}
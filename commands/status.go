@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/ailispaw/talk2docker/client"
+)
+
+const (
+	PING_TIMEOUT  = 5 * time.Second
+	WAIT_INTERVAL = time.Second
+)
+
+var (
+	boolStatus  bool
+	waitTimeout time.Duration
+)
+
+type HostStatus struct {
+	Host       string
+	Reachable  bool
+	Latency    time.Duration
+	Version    string
+	ApiVersion string
+	CertExpiry time.Time
+	Error      string
+}
+
+var cmdWaitHost = &cobra.Command{
+	Use:   "wait [NAME]",
+	Short: "Wait until the host becomes reachable",
+	Long:  APP_NAME + " host wait - Wait until the host becomes reachable",
+	Run:   waitHost,
+}
+
+func init() {
+	flags := cmdWaitHost.Flags()
+	flags.DurationVarP(&waitTimeout, "timeout", "t", 60*time.Second, "Give up after the duration, e.g. 2m")
+	cmdHost.AddCommand(cmdWaitHost)
+}
+
+func getHostStatus(ctx *cobra.Command, name string) (*HostStatus, error) {
+	docker, err := client.NewDockerClient(configPath, name, ctx.Out())
+	if err != nil {
+		return nil, err
+	}
+
+	ping, err := docker.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &HostStatus{
+		Host:       name,
+		Reachable:  true,
+		Latency:    ping.Latency,
+		CertExpiry: ping.CertExpiry,
+	}
+
+	version, err := docker.Version()
+	if err != nil {
+		return nil, err
+	}
+	status.Version = version.Version
+	status.ApiVersion = version.ApiVersion
+
+	return status, nil
+}
+
+// Pings the hosts in parallel, where unreachable hosts have their errors
+func getHostStatuses(ctx *cobra.Command, names []string) map[string]*HostStatus {
+	results := forEachHostWithin(names, PING_TIMEOUT, func(name string) (interface{}, error) {
+		return getHostStatus(ctx, name)
+	})
+
+	statuses := map[string]*HostStatus{}
+	for _, result := range results {
+		if result.Error != nil {
+			statuses[result.Host] = &HostStatus{
+				Host:  result.Host,
+				Error: result.Error.Error(),
+			}
+			continue
+		}
+		statuses[result.Host] = result.Value.(*HostStatus)
+	}
+	return statuses
+}
+
+func formatCertExpiry(expiry time.Time) string {
+	if expiry.IsZero() {
+		return ""
+	}
+	if expiry.Before(time.Now()) {
+		return fmt.Sprintf("%s (expired)", FormatTimeValue(expiry))
+	}
+	return FormatTimeValue(expiry)
+}
+
+func waitHost(ctx *cobra.Command, args []string) {
+	if len(args) > 0 {
+		hostName = args[0]
+	}
+
+	if waitTimeout <= 0 {
+		ErrorExit(ctx, "Needs a positive --timeout")
+	}
+
+	config, err := client.LoadConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	host, err := config.GetHost(hostName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	docker, err := client.NewDockerClient(configPath, host.Name, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		// Each attempt is within PING_TIMEOUT, and never beyond the deadline
+		timeout := deadline.Sub(time.Now())
+		if timeout > PING_TIMEOUT {
+			timeout = PING_TIMEOUT
+		}
+
+		err := forEachHostWithin([]string{host.Name}, timeout, func(name string) (interface{}, error) {
+			return docker.Ping()
+		})[0].Error
+		if err == nil {
+			ctx.Printf("%s is reachable\n", host.Name)
+			return
+		}
+		log.Debugf("%s: %s", host.Name, err)
+
+		if !time.Now().Add(WAIT_INTERVAL).Before(deadline) {
+			log.Fatalf("%s didn't become reachable in %s: %s", host.Name, waitTimeout, err)
+		}
+		time.Sleep(WAIT_INTERVAL)
+	}
+}
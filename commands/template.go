@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"join": func(list []string, sep string) string {
+		return strings.Join(list, sep)
+	},
+	"truncate": func(s string, maxlen int) string {
+		return Truncate(s, maxlen)
+	},
+	"size": func(value interface{}) (string, error) {
+		n, err := toFloat(value)
+		if err != nil {
+			return "", err
+		}
+		return FormatSize(n), nil
+	},
+	"since": func(value interface{}) (string, error) {
+		var t time.Time
+		switch v := value.(type) {
+		case time.Time:
+			t = v
+		case *time.Time:
+			t = *v
+		default:
+			n, err := toFloat(value)
+			if err != nil {
+				return "", err
+			}
+			t = time.Unix(int64(n), 0)
+		}
+		return FormatSince(t, time.Now()), nil
+	},
+}
+
+func toFloat(value interface{}) (float64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	}
+	return 0, fmt.Errorf("Not a number: %v", value)
+}
+
+// Renders each item of a slice, or a single value, with a Go template per line
+func PrintInTemplate(out io.Writer, format string, value interface{}) error {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(format)
+	if err != nil {
+		return fmt.Errorf("Invalid format: %s", err)
+	}
+
+	var items []interface{}
+	v := reflect.ValueOf(value)
+	if (v.Kind() == reflect.Slice) || (v.Kind() == reflect.Array) {
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, v.Index(i).Interface())
+		}
+	} else {
+		items = append(items, value)
+	}
+
+	for _, item := range items {
+		buf := new(bytes.Buffer)
+		if err := tmpl.Execute(buf, item); err != nil {
+			return err
+		}
+		buf.WriteString("\n")
+		if _, err := io.Copy(out, buf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+func TestPrintInTemplate(t *testing.T) {
+	images := []api.Image{
+		{Id: "8c2e06607696bd4afb3d03b687e361cc43cf8ec1a4a725bc96e39f05ba97dd55", RepoTags: []string{"busybox:latest", "busybox:1"}, VirtualSize: 2430000},
+		{Id: "4986bf8c15363d1c5d15512d5266f8777bfba4974ac56e3270e7760f6f0a8125", RepoTags: []string{"<none>:<none>"}, VirtualSize: 999},
+	}
+
+	out := new(bytes.Buffer)
+	if err := PrintInTemplate(out, `{{truncate .Id 12}} {{join .RepoTags ","}} {{size .VirtualSize}}`, images); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expected := "8c2e06607696 busybox:latest,busybox:1 2.4 MB\n4986bf8c1536 <none>:<none> 999 B\n"
+	if out.String() != expected {
+		t.Errorf("got %v\nwant %v", out.String(), expected)
+	}
+
+	out.Reset()
+	if err := PrintInTemplate(out, `{{json .RepoTags}}`, images[0]); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expected = "[\"busybox:latest\",\"busybox:1\"]\n"
+	if out.String() != expected {
+		t.Errorf("got %v\nwant %v", out.String(), expected)
+	}
+
+	if err := PrintInTemplate(out, `{{.Id`, images); err == nil {
+		t.Errorf("%v", "An invalid template should be an error.")
+	}
+}
@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yungsang/tablewriter"
+
+	"github.com/ailispaw/talk2docker/api"
+	"github.com/ailispaw/talk2docker/client"
+)
+
+const (
+	TREE_FORMAT_DOT  = "dot"
+	TREE_FORMAT_JSON = "json"
+)
+
+var (
+	treeFormat string
+)
+
+var reImageIdPrefix = regexp.MustCompile(`^[0-9a-f]{4,}$`)
+
+type ImageNode struct {
+	ID          string
+	ParentID    string
+	RepoTags    []string
+	VirtualSize int64
+	Used        bool
+}
+
+var cmdShowImageTree = &cobra.Command{
+	Use:   "tree [NAME[:TAG]]",
+	Short: "Show the tree of images",
+	Long:  APP_NAME + " image tree - Show the tree of images",
+	Run:   showImageTree,
+}
+
+func init() {
+	flags := cmdShowImageTree.Flags()
+	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	flags.StringVar(&treeFormat, "graph", "", "Export the tree in the format: dot or json")
+	cmdImage.AddCommand(cmdShowImageTree)
+}
+
+func showImageTree(ctx *cobra.Command, args []string) {
+	switch treeFormat {
+	case "", TREE_FORMAT_DOT, TREE_FORMAT_JSON:
+	default:
+		ErrorExit(ctx, fmt.Sprintf("Invalid graph format: %s (dot or json)", treeFormat))
+	}
+
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	images, err := docker.ListImages(true, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(args) > 0 {
+		images = filterImageTree(images, args[0])
+		if len(images) == 0 {
+			log.Fatalf("No such image: %s", args[0])
+		}
+	}
+
+	used, err := getUsedImages(docker)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var nodes []ImageNode
+	for _, image := range images {
+		nodes = append(nodes, ImageNode{
+			ID:          image.Id,
+			ParentID:    image.ParentId,
+			RepoTags:    image.RepoTags,
+			VirtualSize: image.VirtualSize,
+			Used:        used[image.Id],
+		})
+	}
+
+	switch {
+	case treeFormat == TREE_FORMAT_DOT:
+		printImageTreeInDot(ctx.Out(), nodes)
+		return
+	case treeFormat == TREE_FORMAT_JSON:
+		if err := PrintInJSON(ctx.Out(), nodes); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case boolYAML || boolJSON || (format != ""):
+		if err := FormatPrint(ctx.Out(), nodes); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	roots, parents := getImageTree(images)
+	items := walkTree(roots, parents, used, "", [][]string{})
+
+	header := []string{
+		"ID",
+		"Name:Tags",
+		getSizeHeader(),
+		"Used",
+	}
+
+	PrintInTable(ctx.Out(), header, items, 0, tablewriter.ALIGN_DEFAULT)
+}
+
+// Returns the images without parents in the list, and the children of each image
+func getImageTree(images []api.Image) ([]api.Image, map[string][]api.Image) {
+	ids := map[string]bool{}
+	for _, image := range images {
+		ids[image.Id] = true
+	}
+
+	roots := []api.Image{}
+	parents := map[string][]api.Image{}
+	for _, image := range images {
+		if !ids[image.ParentId] {
+			roots = append(roots, image)
+		} else {
+			parents[image.ParentId] = append(parents[image.ParentId], image)
+		}
+	}
+	return roots, parents
+}
+
+// Returns the images matching the name, and their ancestors and descendants
+func filterImageTree(images []api.Image, name string) []api.Image {
+	var (
+		byId     = map[string]api.Image{}
+		children = map[string][]string{}
+		selected = map[string]bool{}
+		queue    []string
+	)
+
+	for _, image := range images {
+		byId[image.Id] = image
+		children[image.ParentId] = append(children[image.ParentId], image.Id)
+	}
+
+	var matched []api.Image
+	for _, image := range images {
+		if matchImageByName(image.RepoTags, name) {
+			matched = append(matched, image)
+		}
+	}
+
+	// Takes the name as an ID prefix only if it looks like one, or nothing is tagged with it
+	if (len(matched) == 0) || reImageIdPrefix.MatchString(name) {
+		for _, image := range images {
+			if strings.HasPrefix(image.Id, name) && !matchImageByName(image.RepoTags, name) {
+				matched = append(matched, image)
+			}
+		}
+	}
+
+	for _, image := range matched {
+		for id := image.ParentId; id != ""; id = byId[id].ParentId {
+			if selected[id] {
+				break
+			}
+			selected[id] = true
+		}
+		queue = append(queue, image.Id)
+	}
+
+	// Only the matching images are expanded to their descendants
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		selected[id] = true
+		queue = append(queue, children[id]...)
+	}
+
+	var filtered []api.Image
+	for _, image := range images {
+		if selected[image.Id] {
+			filtered = append(filtered, image)
+		}
+	}
+	return filtered
+}
+
+func printImageTreeInDot(out io.Writer, nodes []ImageNode) {
+	fmt.Fprintln(out, "digraph images {")
+	fmt.Fprintln(out, "  node [shape=box, fontname=\"monospace\"];")
+	for _, node := range nodes {
+		label := Truncate(node.ID, 12)
+		if getImageTag(node.RepoTags) != "" {
+			label += "\\n" + strings.Join(node.RepoTags, "\\n")
+		}
+		attrs := fmt.Sprintf("label=\"%s\"", label)
+		if node.Used {
+			attrs += ", style=filled, fillcolor=\"lightblue\""
+		}
+		fmt.Fprintf(out, "  \"%s\" [%s];\n", Truncate(node.ID, 12), attrs)
+	}
+	for _, node := range nodes {
+		if node.ParentID == "" {
+			continue
+		}
+		fmt.Fprintf(out, "  \"%s\" -> \"%s\";\n", Truncate(node.ParentID, 12), Truncate(node.ID, 12))
+	}
+	fmt.Fprintln(out, "}")
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ailispaw/talk2docker/api"
+)
+
+func TestFilterImageTree(t *testing.T) {
+	images := []api.Image{
+		{Id: "1111", RepoTags: []string{"<none>:<none>"}},
+		{Id: "2222", ParentId: "1111", RepoTags: []string{"debian:wheezy"}},
+		{Id: "add0", ParentId: "2222", RepoTags: []string{"app:1"}},
+		{Id: "add1", ParentId: "add0", RepoTags: []string{"<none>:<none>"}},
+		{Id: "3333", ParentId: "2222", RepoTags: []string{"add:latest"}},
+		{Id: "beef", RepoTags: []string{"busybox:latest"}},
+	}
+
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"app", []string{"1111", "2222", "add0", "add1"}},
+		{"add", []string{"1111", "2222", "3333"}},
+		{"add0", []string{"1111", "2222", "add0", "add1"}},
+		{"beef", []string{"beef"}},
+		{"22", []string{"1111", "2222", "add0", "add1", "3333"}},
+		{"missing", nil},
+	}
+
+	for _, test := range tests {
+		var ids []string
+		for _, image := range filterImageTree(images, test.name) {
+			ids = append(ids, image.Id)
+		}
+		if !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("%s: got %v\nwant %v", test.name, ids, test.expected)
+		}
+	}
+}
@@ -162,7 +162,12 @@ func uploadToVolume(ctx *cobra.Command, args []string) {
 		path = arr[1]
 	)
 
-	volumes, err := getVolumes(ctx)
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	volumes, err := getVolumes(docker)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -183,10 +188,7 @@ func uploadToVolume(ctx *cobra.Command, args []string) {
 		log.Fatal(err)
 	}
 
-	docker, err := client.NewDockerClient(configPath, hostName, f)
-	if err != nil {
-		log.Fatal(err)
-	}
+	docker = docker.WithOutput(f)
 
 	ctx.Printf("Uploading %s into %s\n", args[0], args[1])
 
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/yungsang/tablewriter"
 	"gopkg.in/yaml.v2"
 )
@@ -76,7 +77,102 @@ func FormatFloat(n float64) string {
 	return FormatNumber(n, 3)
 }
 
+// Formats bytes in decimal units as the tables do in MB
+func FormatSize(n float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for ; (n >= 1000) && (i < len(units)-1); i++ {
+		n /= 1000
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d %s", int64(n), units[i])
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
+
+// Formats the time elapsed since t, e.g. "3 hours ago"
+func FormatSince(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < 0 {
+		d = 0
+	}
+
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+
+	switch {
+	case d < time.Minute:
+		return plural(int64(d/time.Second), "second")
+	case d < time.Hour:
+		return plural(int64(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return plural(int64(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		return plural(int64(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		return plural(int64(d/(30*24*time.Hour)), "month")
+	}
+	return plural(int64(d/(365*24*time.Hour)), "year")
+}
+
+// Compares two texts line by line, and returns them prefixed with " ", "-" or "+"
+func DiffLines(a, b string) []string {
+	x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common lines of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for (i < len(x)) || (j < len(y)) {
+		switch {
+		case (i < len(x)) && (j < len(y)) && (x[i] == y[j]):
+			lines = append(lines, " "+x[i])
+			i++
+			j++
+		case (i < len(x)) && ((j == len(y)) || (lcs[i+1][j] >= lcs[i][j+1])):
+			lines = append(lines, "-"+x[i])
+			i++
+		default:
+			lines = append(lines, "+"+y[j])
+			j++
+		}
+	}
+	return lines
+}
+
 func PrintInTable(out io.Writer, header []string, items [][]string, width, align int) {
+	switch output {
+	case OUTPUT_CSV, OUTPUT_TSV:
+		comma := ','
+		if output == OUTPUT_TSV {
+			comma = '\t'
+		}
+		if err := PrintInCSV(out, header, items, comma); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	table := tablewriter.NewWriter(out)
 	if !boolNoHeader {
 		if header != nil {
@@ -97,6 +193,8 @@ func PrintInTable(out io.Writer, header []string, items [][]string, width, align
 
 func FormatPrint(out io.Writer, value interface{}) error {
 	switch {
+	case format != "":
+		return PrintInTemplate(out, format, value)
 	case boolJSON:
 		return PrintInJSON(out, value)
 	}
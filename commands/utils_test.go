@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -78,3 +79,54 @@ func TestFormatFloat(t *testing.T) {
 		t.Errorf("got %v\nwant %v", actual, expected)
 	}
 }
+
+func TestFormatSize(t *testing.T) {
+	tests := map[float64]string{
+		0:          "0 B",
+		999:        "999 B",
+		1500:       "1.5 KB",
+		2500000:    "2.5 MB",
+		1230000000: "1.2 GB",
+	}
+
+	for n, expected := range tests {
+		if actual := FormatSize(n); actual != expected {
+			t.Errorf("got %v\nwant %v", actual, expected)
+		}
+	}
+}
+
+func TestFormatSince(t *testing.T) {
+	now := time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[time.Duration]string{
+		30 * time.Second:    "30 seconds ago",
+		time.Minute:         "1 minute ago",
+		3 * time.Hour:       "3 hours ago",
+		2 * 24 * time.Hour:  "2 days ago",
+		90 * 24 * time.Hour: "3 months ago",
+	}
+
+	for d, expected := range tests {
+		if actual := FormatSince(now.Add(-d), now); actual != expected {
+			t.Errorf("got %v\nwant %v", actual, expected)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	a := "default: local\nhosts:\n- name: local\n"
+	b := "version: 1\ndefault: local\nhosts:\n- name: remote\n"
+
+	expected := []string{
+		"+version: 1",
+		" default: local",
+		" hosts:",
+		"-- name: local",
+		"+- name: remote",
+	}
+	actual := DiffLines(a, b)
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got %v\nwant %v", actual, expected)
+	}
+}
@@ -23,10 +23,14 @@ var cmdVersion = &cobra.Command{
 func init() {
 	flags := cmdVersion.Flags()
 	flags.BoolVarP(&boolNoHeader, "no-header", "n", false, "Omit the header")
+	addMultiHostFlags(flags)
 }
 
 func showVersion(ctx *cobra.Command, args []string) {
-	data := map[string]api.Version{}
+	var (
+		data = map[string]api.Version{}
+		keys = []string{APP_NAME} // in the order to display
+	)
 
 	data[APP_NAME] = api.Version{
 		Version:       version.APP_VERSION,
@@ -40,13 +44,19 @@ func showVersion(ctx *cobra.Command, args []string) {
 
 	var e error
 
-	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	hosts, err := getTargetHosts()
 	if err != nil {
 		e = err
 		goto Display
 	}
 
-	{
+	if hosts == nil {
+		docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+		if err != nil {
+			e = err
+			goto Display
+		}
+
 		dockerVersion, err := docker.Version()
 		if err != nil {
 			e = err
@@ -54,10 +64,30 @@ func showVersion(ctx *cobra.Command, args []string) {
 		}
 
 		data["Docker Server"] = *dockerVersion
+		keys = append(keys, "Docker Server")
+	} else {
+		results := forEachHost(hosts, func(name string) (interface{}, error) {
+			docker, err := client.NewDockerClient(configPath, name, ctx.Out())
+			if err != nil {
+				return nil, err
+			}
+			return docker.Version()
+		})
+
+		for _, result := range results {
+			if result.Error == nil {
+				// Prefixed not to overwrite the client with a host of the same name
+				key := "Docker Server: " + result.Host
+				data[key] = *result.Value.(*api.Version)
+				keys = append(keys, key)
+			}
+		}
+
+		e = reportHostErrors(results)
 	}
 
 Display:
-	if boolYAML || boolJSON {
+	if boolYAML || boolJSON || (format != "") {
 		if err := FormatPrint(ctx.Out(), data); err != nil {
 			log.Fatal(err)
 		}
@@ -69,7 +99,8 @@ Display:
 
 	var items [][]string
 
-	for key, value := range data {
+	for _, key := range keys {
+		value := data[key]
 		out := []string{
 			key,
 			value.Version,
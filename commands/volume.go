@@ -171,7 +171,12 @@ func init() {
 }
 
 func listVolumes(ctx *cobra.Command, args []string) {
-	volumes, err := getVolumes(ctx)
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	volumes, err := getVolumes(docker)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -195,7 +200,7 @@ func listVolumes(ctx *cobra.Command, args []string) {
 		return
 	}
 
-	if boolYAML || boolJSON {
+	if boolYAML || boolJSON || (format != "") {
 		if err := FormatPrint(ctx.Out(), volumes); err != nil {
 			log.Fatal(err)
 		}
@@ -277,7 +282,12 @@ func inspectVolumes(ctx *cobra.Command, args []string) {
 		ErrorExit(ctx, "Needs an argument <ID|CONTAINER-NAME:PATH> at least to inspect")
 	}
 
-	volumes, err := getVolumes(ctx)
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	volumes, err := getVolumes(docker)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -310,7 +320,12 @@ func removeVolumes(ctx *cobra.Command, args []string) {
 		ErrorExit(ctx, "Needs an argument <ID> at least to inspect")
 	}
 
-	volumes, err := getVolumes(ctx)
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	volumes, err := getVolumes(docker)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -350,12 +365,7 @@ func removeVolumes(ctx *cobra.Command, args []string) {
 	}
 }
 
-func getVolumes(ctx *cobra.Command) (Volumes, error) {
-	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
-	if err != nil {
-		return nil, err
-	}
-
+func getVolumes(docker *api.DockerClient) (Volumes, error) {
 	info, err := docker.Info()
 	if err != nil {
 		return nil, err
@@ -389,7 +399,7 @@ func getVolumes(ctx *cobra.Command) (Volumes, error) {
 	cid, err = docker.CreateContainer("", config, hostConfig)
 	if err != nil {
 		if apiErr, ok := err.(api.Error); ok && (apiErr.StatusCode == 404) {
-			if err := pullImageInSilence(ctx, config.Image); err != nil {
+			if err := pullImageInSilence(docker, config.Image); err != nil {
 				return nil, err
 			}
 
@@ -439,7 +449,7 @@ func getVolumes(ctx *cobra.Command) (Volumes, error) {
 		return nil, err
 	}
 
-	mounts, err := getMounts(ctx)
+	mounts, err := getMounts(docker)
 	if err != nil {
 		return nil, err
 	}
@@ -455,12 +465,7 @@ func getVolumes(ctx *cobra.Command) (Volumes, error) {
 	return volumes, nil
 }
 
-func getMounts(ctx *cobra.Command) ([]*Mount, error) {
-	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
-	if err != nil {
-		return nil, err
-	}
-
+func getMounts(docker *api.DockerClient) ([]*Mount, error) {
 	containers, err := docker.ListContainers(true, false, 0, "", "", nil)
 	if err != nil {
 		return nil, err
@@ -559,7 +564,7 @@ func removeVolume(ctx *cobra.Command, volume *Volume) error {
 	cid, err = docker.CreateContainer("", config, hostConfig)
 	if err != nil {
 		if apiErr, ok := err.(api.Error); ok && (apiErr.StatusCode == 404) {
-			if err := pullImageInSilence(ctx, config.Image); err != nil {
+			if err := pullImageInSilence(docker, config.Image); err != nil {
 				return err
 			}
 
@@ -589,7 +594,12 @@ func exportVolume(ctx *cobra.Command, args []string) {
 		ErrorExit(ctx, "Needs an argument <ID|CONTAINER-NAME:PATH> to export")
 	}
 
-	volumes, err := getVolumes(ctx)
+	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	volumes, err := getVolumes(docker)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -608,16 +618,11 @@ func exportVolume(ctx *cobra.Command, args []string) {
 
 	hostConfig.Binds = []string{volume.Path + ":/" + volume.ID}
 
-	docker, err := client.NewDockerClient(configPath, hostName, ctx.Out())
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	var cid string
 	cid, err = docker.CreateContainer("", config, hostConfig)
 	if err != nil {
 		if apiErr, ok := err.(api.Error); ok && (apiErr.StatusCode == 404) {
-			if err := pullImageInSilence(ctx, config.Image); err != nil {
+			if err := pullImageInSilence(docker, config.Image); err != nil {
 				log.Fatal(err)
 			}
 